@@ -4,12 +4,14 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 
 	"github.com/ramonmacias/librarium/internal/app/interface/api"
+	"github.com/ramonmacias/librarium/internal/config"
 )
 
 func main() {
@@ -17,10 +19,21 @@ func main() {
 	flag.DurationVar(&wait, "graceful-timeout", time.Second*15, "the duration for which the server gracefully wait for existing connections to finish - e.g. 15s or 1m")
 	flag.Parse()
 
-	r := api.BuildRouter()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Error loading the configuration: %v", err)
+	}
+
+	// Running "librarium seed" fills the database with sample data and exits
+	if flag.Arg(0) == "seed" {
+		seed(cfg)
+		return
+	}
+
+	r := api.BuildRouter(cfg)
 
 	srv := &http.Server{
-		Addr: "0.0.0.0:8080",
+		Addr: cfg.Address,
 		// Good practice to set timeouts to avoid Slowloris attacks.
 		WriteTimeout: time.Second * 15,
 		ReadTimeout:  time.Second * 15,
@@ -28,12 +41,21 @@ func main() {
 		Handler:      r, // Pass our instance of gorilla/mux in.
 	}
 
+	// Bind the address before announcing readiness, so the readiness probe
+	// only passes once the server can really accept connections
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		log.Fatalf("Error listening on %s: %v", cfg.Address, err)
+	}
+
 	// Run our server in a goroutine so that it doesn't block.
 	go func() {
-		if err := srv.ListenAndServe(); err != nil {
+		if err := srv.Serve(listener); err != nil {
 			log.Println(err)
 		}
 	}()
+	api.SetReady(true)
+	log.Printf("Ready, listening on %s", cfg.Address)
 
 	c := make(chan os.Signal, 1)
 	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C)
@@ -43,6 +65,12 @@ func main() {
 	// Block until we receive our signal.
 	<-c
 
+	// Stop announcing readiness and keep serving during the drain period, so
+	// the load balancer notices it before the server stops accepting traffic
+	api.SetReady(false)
+	log.Printf("Draining for %s", cfg.DrainPeriod)
+	time.Sleep(cfg.DrainPeriod)
+
 	// Create a deadline to wait for.
 	ctx, cancel := context.WithTimeout(context.Background(), wait)
 	defer cancel()
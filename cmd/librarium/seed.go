@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"log"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/postgres"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/sqlite"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/config"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type sampleUser struct {
+	email    string
+	name     string
+	lastName string
+}
+
+var (
+	sampleUsers = []sampleUser{
+		{email: "ada.lovelace@example.com", name: "Ada", lastName: "Lovelace"},
+		{email: "alan.turing@example.com", name: "Alan", lastName: "Turing"},
+		{email: "grace.hopper@example.com", name: "Grace", lastName: "Hopper"},
+		{email: "edsger.dijkstra@example.com", name: "Edsger", lastName: "Dijkstra"},
+		{email: "barbara.liskov@example.com", name: "Barbara", lastName: "Liskov"},
+	}
+	sampleBooks = []relational.Book{
+		{Title: "The Go Programming Language", ISBN: "9780134190440", Price: 34.99, Language: "eng", Location: "A-1"},
+		{Title: "Clean Architecture", ISBN: "9780134494166", Price: 29.99, Language: "eng", Location: "A-2"},
+		{Title: "Clean Code", ISBN: "9780132350884", Price: 37.49, Language: "eng", Location: "A-2"},
+		{Title: "The Pragmatic Programmer", ISBN: "9780135957059", Price: 41.99, Language: "eng", Location: "A-3"},
+		{Title: "Domain-Driven Design", ISBN: "9780321125217", Price: 54.99, Language: "eng", Location: "A-3"},
+		{Title: "Refactoring", ISBN: "9780134757599", Price: 44.99, Language: "eng", Location: "A-4"},
+		{Title: "Designing Data-Intensive Applications", ISBN: "9781449373320", Price: 39.99, Language: "eng", Location: "B-1"},
+		{Title: "Structure and Interpretation of Computer Programs", ISBN: "9780262510875", Price: 49.5, Language: "eng", Location: "B-2"},
+	}
+)
+
+// seed populates every database configured with sample users and books,
+// postgres is migrated first and sqlite migrates when it's opened. The
+// samples already stored are skipped, so it's safe to run it more than once,
+// but it fails when a sample can't be stored for any other reason
+func seed(cfg *config.Config) {
+	if cfg.Postgres == nil && cfg.SQLitePath == "" {
+		log.Fatal("No database is configured, set the POSTGRES_* values or SQLITE_PATH")
+	}
+	if cfg.Postgres != nil {
+		conn := postgres.NewClient(cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Database, cfg.Postgres.Password).Connect()
+		if err := conn.Migrate(); err != nil {
+			log.Fatalf("Error running the migrations: %v", err)
+		}
+		seedStorage("postgres", postgres.NewStorage(conn))
+	}
+	if cfg.SQLitePath != "" {
+		seedStorage("sqlite", sqlite.NewStorage(sqlite.NewClient(cfg.SQLitePath).Connect()))
+	}
+}
+
+func seedStorage(name string, storage repository.Storage) {
+	var users, books, existing, failed int
+	count := func(seeded *int, sample string, err error) {
+		switch {
+		case err == nil:
+			*seeded++
+		case errors.Is(err, domainerr.ErrConflict):
+			existing++
+		default:
+			log.Printf("Error seeding %s on %s: %v", sample, name, err)
+			failed++
+		}
+	}
+
+	userInteractor := usecase.NewUserInteractor(storage.Users(), service.NewUserService(storage.Users()))
+	for _, user := range sampleUsers {
+		count(&users, user.email, userInteractor.RegisterUser(user.email, user.name, user.lastName))
+	}
+	bookInteractor := usecase.NewBookInteractor(storage.Books(), service.NewBookService(storage.Books()))
+	for _, book := range sampleBooks {
+		count(&books, book.ISBN, bookInteractor.RegisterBook(book))
+	}
+	if failed > 0 {
+		log.Fatalf("%d samples couldn't be seeded on %s, check the errors above", failed, name)
+	}
+	log.Printf("Seeded %d users and %d books on %s, %d samples were already there", users, books, name, existing)
+}
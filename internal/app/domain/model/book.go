@@ -1,9 +1,27 @@
 package model
 
+import "time"
+
 type Book interface {
 	GetID() string
 	GetTitle() string
 	GetISBN() string
 	GetPrice() float64
+	// GetLanguage is the ISO 639 code of the language the book is written in
+	GetLanguage() string
+	// GetOriginalLanguage is the language the book was first written in, it's
+	// empty when the book is not a translation
+	GetOriginalLanguage() string
+	// GetLocation is the shelf code where the book is kept
+	GetLocation() string
 	GetUser() *User
+	// GetSupplierID is the supplier the book was bought from, it's empty
+	// when it's not known
+	GetSupplierID() string
+	// GetCreatedAt is when the book was added to the catalog, it's the zero
+	// time for the books not stored yet
+	GetCreatedAt() time.Time
+	// GetUpdatedAt is when the book was last changed, it's the zero time for
+	// the books not stored yet
+	GetUpdatedAt() time.Time
 }
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// BookChange is an update of a book, it keeps the fields that changed with
+// the values they had before and after it
+type BookChange struct {
+	bookID    string
+	fields    map[string]FieldChange
+	changedAt time.Time
+}
+
+// FieldChange is the value of a field before and after an update
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func NewBookChange(bookID string, fields map[string]FieldChange, changedAt time.Time) *BookChange {
+	return &BookChange{
+		bookID:    bookID,
+		fields:    fields,
+		changedAt: changedAt,
+	}
+}
+
+func (c *BookChange) GetBookID() string {
+	return c.bookID
+}
+
+// GetFields returns the changes by field, the keys are the names of the
+// fields on the API
+func (c *BookChange) GetFields() map[string]FieldChange {
+	return c.fields
+}
+
+func (c *BookChange) GetChangedAt() time.Time {
+	return c.changedAt
+}
+
+// DiffBooks returns the fields that differ between the stored book and its
+// update, it's empty when nothing changed
+func DiffBooks(before, after Book) map[string]FieldChange {
+	fields := map[string]FieldChange{}
+	diff := func(name, from, to string) {
+		if from != to {
+			fields[name] = FieldChange{From: from, To: to}
+		}
+	}
+	diff("title", before.GetTitle(), after.GetTitle())
+	diff("isbn", before.GetISBN(), after.GetISBN())
+	diff("price", formatPrice(before.GetPrice()), formatPrice(after.GetPrice()))
+	diff("language", before.GetLanguage(), after.GetLanguage())
+	diff("original_language", before.GetOriginalLanguage(), after.GetOriginalLanguage())
+	diff("location", before.GetLocation(), after.GetLocation())
+	diff("supplier_id", before.GetSupplierID(), after.GetSupplierID())
+	return fields
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
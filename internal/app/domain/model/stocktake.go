@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Stocktake is an inventory audit, the scans are compared with the catalog
+// to find the missing and misplaced books. The location limits the audit to
+// a shelf, it's empty when the whole catalog is audited
+type Stocktake struct {
+	id        string
+	location  string
+	startedAt time.Time
+	closedAt  time.Time
+}
+
+func NewStocktake(id, location string, startedAt, closedAt time.Time) *Stocktake {
+	return &Stocktake{
+		id:        id,
+		location:  location,
+		startedAt: startedAt,
+		closedAt:  closedAt,
+	}
+}
+
+func (s *Stocktake) GetID() string {
+	return s.id
+}
+
+func (s *Stocktake) GetLocation() string {
+	return s.location
+}
+
+func (s *Stocktake) GetStartedAt() time.Time {
+	return s.startedAt
+}
+
+// GetClosedAt is the zero time while the stocktake is open
+func (s *Stocktake) GetClosedAt() time.Time {
+	return s.closedAt
+}
+
+func (s *Stocktake) IsClosed() bool {
+	return !s.closedAt.IsZero()
+}
+
+// StocktakeScan is a barcode read during a stocktake, the code is the ISBN
+// or the id of the book and the location is the shelf where it was found
+type StocktakeScan struct {
+	code      string
+	location  string
+	scannedAt time.Time
+}
+
+func NewStocktakeScan(code, location string, scannedAt time.Time) *StocktakeScan {
+	return &StocktakeScan{
+		code:      code,
+		location:  location,
+		scannedAt: scannedAt,
+	}
+}
+
+func (s *StocktakeScan) GetCode() string {
+	return s.code
+}
+
+func (s *StocktakeScan) GetLocation() string {
+	return s.location
+}
+
+func (s *StocktakeScan) GetScannedAt() time.Time {
+	return s.scannedAt
+}
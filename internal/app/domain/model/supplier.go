@@ -0,0 +1,35 @@
+package model
+
+// Supplier is a vendor the library buys books from, the categories are the
+// kinds of books it supplies
+type Supplier struct {
+	id         string
+	name       string
+	contact    string
+	categories []string
+}
+
+func NewSupplier(id, name, contact string, categories []string) *Supplier {
+	return &Supplier{
+		id:         id,
+		name:       name,
+		contact:    contact,
+		categories: categories,
+	}
+}
+
+func (s *Supplier) GetID() string {
+	return s.id
+}
+
+func (s *Supplier) GetName() string {
+	return s.name
+}
+
+func (s *Supplier) GetContact() string {
+	return s.contact
+}
+
+func (s *Supplier) GetCategories() []string {
+	return s.categories
+}
@@ -1,11 +1,36 @@
 package repository
 
-import "github.com/ramonmacias/librarium/internal/app/domain/model"
+import (
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
 
 type BookRepository interface {
 	FindAll() ([]model.Book, error)
+	Count() (int, error)
 	FindByID(id string) (model.Book, error)
+	// FindByIDs skips the ids that don't exist
+	FindByIDs(ids []string) ([]model.Book, error)
 	FindByISBN(ISBN string) (model.Book, error)
+	// FindByLanguage ignores the case, the language is given in lower case
+	FindByLanguage(language string) ([]model.Book, error)
+	// CountByLanguage counts the books of each language, the keys are in
+	// lower case and the books without language are not counted
+	CountByLanguage() (map[string]int, error)
+	// FindBySupplier returns the books bought from the supplier, the newest
+	// first
+	FindBySupplier(supplierID string) ([]model.Book, error)
+	// FindAddedSince returns the books added after the given time, the
+	// newest first
+	FindAddedSince(since time.Time) ([]model.Book, error)
+	// FindHistory returns the changes made to the book, the oldest first
+	FindHistory(id string) ([]*model.BookChange, error)
+	// Save creates the book when it has no id, otherwise it updates it and
+	// records the fields changed on its history
 	Save(book model.Book) error
 	Delete(id string) error
+	// DeleteMany removes all the books or none of them, it fails with a
+	// conflict when any of them is missing or rented
+	DeleteMany(ids []string) error
 }
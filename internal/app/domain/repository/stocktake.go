@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+type StocktakeRepository interface {
+	// FindAll returns the stocktakes, the newest first
+	FindAll() ([]*model.Stocktake, error)
+	FindByID(id string) (*model.Stocktake, error)
+	// Create stores a new stocktake and returns its id, the clients need it
+	// to resume the stocktake
+	Create(stocktake *model.Stocktake) (string, error)
+	Close(id string, closedAt time.Time) error
+	AddScans(id string, scans []*model.StocktakeScan) error
+	// FindScans returns the scans of the stocktake in the order they were
+	// added
+	FindScans(id string) ([]*model.StocktakeScan, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+// Storage is implemented by every persistence backend, it gives access to
+// all the repositories stored on it
+type Storage interface {
+	Users() UserRepository
+	Books() BookRepository
+	Tags() TagRepository
+	Suppliers() SupplierRepository
+	Stocktakes() StocktakeRepository
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/ramonmacias/librarium/internal/app/domain/model"
+
+type SupplierRepository interface {
+	// FindAll returns the suppliers sorted by name
+	FindAll() ([]*model.Supplier, error)
+	FindByID(id string) (*model.Supplier, error)
+	// Save creates the supplier when it has no id, otherwise it updates it
+	Save(supplier *model.Supplier) error
+	Delete(id string) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/ramonmacias/librarium/internal/app/domain/model"
+
+type TagRepository interface {
+	AddTag(bookID, tag string) error
+	RemoveTag(bookID, tag string) error
+	// FindBooksByTag skips the removed books, the newest books come first
+	FindBooksByTag(tag string) ([]model.Book, error)
+}
@@ -4,8 +4,13 @@ import "github.com/ramonmacias/librarium/internal/app/domain/model"
 
 type UserRepository interface {
 	FindAll() ([]*model.User, error)
+	Count() (int, error)
+	// FindByEmail ignores the case, emails are unique no matter the case
 	FindByEmail(email string) (*model.User, error)
 	FindByID(id string) (*model.User, error)
 	Save(*model.User) error
 	Delete(*model.User) error
+	// FindDeletedByID finds a deactivated user, the other finders skip them
+	FindDeletedByID(id string) (*model.User, error)
+	Restore(*model.User) error
 }
@@ -1,9 +1,8 @@
 package service
 
 import (
-	"fmt"
-
 	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type BookService struct {
@@ -19,7 +18,7 @@ func NewBookService(repo repository.BookRepository) *BookService {
 func (s *BookService) Duplicated(ISBN string) error {
 	book, err := s.repo.FindByISBN(ISBN)
 	if book != nil {
-		return fmt.Errorf("%s already exists", ISBN)
+		return domainerr.ConflictWith(book.GetID(), "%s already exists", ISBN)
 	}
 	return err
 }
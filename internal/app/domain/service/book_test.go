@@ -1,16 +1,19 @@
 package service_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type FakeBookModel struct{}
 
 func (f FakeBookModel) GetID() string {
-	return ""
+	return "existingBookID"
 }
 
 func (f FakeBookModel) GetTitle() string {
@@ -25,20 +28,52 @@ func (f FakeBookModel) GetPrice() float64 {
 	return 0
 }
 
+func (f FakeBookModel) GetLanguage() string {
+	return ""
+}
+
+func (f FakeBookModel) GetOriginalLanguage() string {
+	return ""
+}
+
+func (f FakeBookModel) GetLocation() string {
+	return ""
+}
+
 func (f FakeBookModel) GetUser() *model.User {
 	return nil
 }
 
+func (f FakeBookModel) GetSupplierID() string {
+	return ""
+}
+
+func (f FakeBookModel) GetCreatedAt() time.Time {
+	return time.Time{}
+}
+
+func (f FakeBookModel) GetUpdatedAt() time.Time {
+	return time.Time{}
+}
+
 type FakeBookRepository struct{}
 
 func (f FakeBookRepository) FindAll() ([]model.Book, error) {
 	return nil, nil
 }
 
+func (f FakeBookRepository) Count() (int, error) {
+	return 0, nil
+}
+
 func (f FakeBookRepository) FindByID(id string) (model.Book, error) {
 	return nil, nil
 }
 
+func (f FakeBookRepository) FindByIDs(ids []string) ([]model.Book, error) {
+	return nil, nil
+}
+
 func (f FakeBookRepository) FindByISBN(ISBN string) (model.Book, error) {
 	if ISBN == "IsbnMustExist" {
 		return FakeBookModel{}, nil
@@ -47,6 +82,26 @@ func (f FakeBookRepository) FindByISBN(ISBN string) (model.Book, error) {
 	}
 }
 
+func (f FakeBookRepository) FindByLanguage(language string) ([]model.Book, error) {
+	return nil, nil
+}
+
+func (f FakeBookRepository) CountByLanguage() (map[string]int, error) {
+	return nil, nil
+}
+
+func (f FakeBookRepository) FindBySupplier(supplierID string) ([]model.Book, error) {
+	return nil, nil
+}
+
+func (f FakeBookRepository) FindHistory(id string) ([]*model.BookChange, error) {
+	return nil, nil
+}
+
+func (f FakeBookRepository) FindAddedSince(since time.Time) ([]model.Book, error) {
+	return nil, nil
+}
+
 func (f FakeBookRepository) Save(book model.Book) error {
 	return nil
 }
@@ -55,6 +110,10 @@ func (f FakeBookRepository) Delete(id string) error {
 	return nil
 }
 
+func (f FakeBookRepository) DeleteMany(ids []string) error {
+	return nil
+}
+
 var (
 	bookService *service.BookService
 )
@@ -73,4 +132,8 @@ func TestDuplicatedBook(t *testing.T) {
 	if res == nil {
 		t.Error("Duplicated should returns an error but returns nothing")
 	}
+	var domainErr *domainerr.Error
+	if !errors.As(res, &domainErr) || domainErr.ResourceID() != "existingBookID" {
+		t.Errorf("Duplicated should return the id of the existing book but returns %v", res)
+	}
 }
@@ -1,9 +1,8 @@
 package service
 
 import (
-	"fmt"
-
 	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type UserService struct {
@@ -19,7 +18,7 @@ func NewUserService(repo repository.UserRepository) *UserService {
 func (s *UserService) Duplicated(email string) error {
 	user, err := s.repo.FindByEmail(email)
 	if user != nil {
-		return fmt.Errorf("%s already exists", email)
+		return domainerr.ConflictWith(user.GetID(), "%s already exists", email)
 	}
 	if err != nil {
 		return err
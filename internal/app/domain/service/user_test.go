@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type FakeUserRepository struct{}
@@ -13,6 +15,10 @@ func (f FakeUserRepository) FindAll() ([]*model.User, error) {
 	return nil, nil
 }
 
+func (f FakeUserRepository) Count() (int, error) {
+	return 0, nil
+}
+
 func (f FakeUserRepository) FindByEmail(email string) (*model.User, error) {
 	if email == "email_already_in_the_system@test.com" {
 		return &model.User{}, nil
@@ -32,6 +38,14 @@ func (f FakeUserRepository) Delete(*model.User) error {
 	return nil
 }
 
+func (f FakeUserRepository) FindDeletedByID(id string) (*model.User, error) {
+	return nil, nil
+}
+
+func (f FakeUserRepository) Restore(*model.User) error {
+	return nil
+}
+
 var (
 	userService *service.UserService
 )
@@ -52,4 +66,7 @@ func TestDuplicatedUser(t *testing.T) {
 	if err == nil {
 		t.Error("Err shouldn't be nil")
 	}
+	if !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Err should be a conflict error but got %v", err)
+	}
 }
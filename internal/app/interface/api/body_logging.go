@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxLoggedBody is the maximum number of bytes logged of each body
+	maxLoggedBody = 4096
+	redacted      = "[REDACTED]"
+)
+
+var (
+	// sensitiveKeys are redacted on any JSON field whose name contains them
+	sensitiveKeys = []string{"password", "token", "secret"}
+)
+
+// teeResponseWriter writes the response to the client while keeping a copy
+// of the first bytes to log them
+type teeResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	buf    bytes.Buffer
+}
+
+func (t *teeResponseWriter) WriteHeader(status int) {
+	t.status = status
+	t.ResponseWriter.WriteHeader(status)
+}
+
+func (t *teeResponseWriter) Write(p []byte) (int, error) {
+	t.size += len(p)
+	if t.buf.Len() < maxLoggedBody {
+		t.buf.Write(p)
+	}
+	return t.ResponseWriter.Write(p)
+}
+
+// bodyLoggingMiddleware logs the request and response bodies of a sample of
+// the requests, sampleRate goes from 0, nothing logged, to 1, everything
+// logged. The sensitive JSON fields are redacted before logging them
+func bodyLoggingMiddleware(sampleRate float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rand.Float64() >= sampleRate {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var requestBody []byte
+			if r.Body != nil {
+				requestBody, _ = io.ReadAll(r.Body)
+				r.Body.Close()
+				r.Body = io.NopCloser(bytes.NewReader(requestBody))
+			}
+			tw := &teeResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(tw, r)
+
+			log.Printf("%s %s request: %s", r.Method, r.URL.Path, redactBody(requestBody, len(requestBody)))
+			log.Printf("%s %s response %d: %s", r.Method, r.URL.Path, tw.status, redactBody(tw.buf.Bytes(), tw.size))
+		})
+	}
+}
+
+// redactBody hides the sensitive fields of a JSON body, the bodies that are
+// not JSON or too long are only logged by size since we can't tell what's
+// inside
+func redactBody(body []byte, size int) string {
+	if size == 0 {
+		return "<empty>"
+	}
+	if size > maxLoggedBody {
+		return fmt.Sprintf("<%d bytes, too long to log>", size)
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes, not JSON>", size)
+	}
+	redactedBody, _ := json.Marshal(redactValue(value))
+	return string(redactedBody)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
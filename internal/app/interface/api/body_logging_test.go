@@ -0,0 +1,50 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBodyLoggingRedactsSecrets(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	var received string
+	handler := bodyLoggingMiddleware(1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"id":"1","accessToken":"abc"}`))
+	}))
+	body := `{"email":"test@test.com","password":"secret1"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/users", strings.NewReader(body)))
+
+	if received != body {
+		t.Errorf("The handler should still get the whole body but got %s", received)
+	}
+	if strings.Contains(logs.String(), "secret1") || strings.Contains(logs.String(), "abc") {
+		t.Errorf("The secrets shouldn't be logged but got %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "test@test.com") || !strings.Contains(logs.String(), redacted) {
+		t.Errorf("Should log the body with the secrets redacted but got %s", logs.String())
+	}
+}
+
+func TestBodyLoggingSampling(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	handler := bodyLoggingMiddleware(0)(fakeListHandler(`[]`))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/books", nil))
+
+	if logs.Len() != 0 {
+		t.Errorf("Nothing should be logged with a 0 sample rate but got %s", logs.String())
+	}
+}
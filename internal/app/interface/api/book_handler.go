@@ -2,24 +2,24 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
-	"github.com/ramonmacias/librarium/internal/app/domain/service"
-	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
-	"github.com/ramonmacias/librarium/internal/app/interface/persistence/postgres"
 	"github.com/ramonmacias/librarium/internal/app/usecase"
 )
 
 type BookRequestBody struct {
-	ID    string  `json:"id"`
-	Title string  `json:"title"`
-	ISBN  string  `json:"isbn"`
-	Price float64 `json:"price"`
+	ID               string  `json:"id"`
+	Title            string  `json:"title"`
+	ISBN             string  `json:"isbn"`
+	Price            float64 `json:"price"`
+	Language         string  `json:"language"`
+	OriginalLanguage string  `json:"original_language"`
+	Location         string  `json:"location"`
+	SupplierID       string  `json:"supplier_id,omitempty"`
 }
 
 //TODO Thing more about this, it makes no sense
@@ -39,130 +39,268 @@ func (b BookRequestBody) GetPrice() float64 {
 	return b.Price
 }
 
+func (b BookRequestBody) GetLanguage() string {
+	return b.Language
+}
+
+func (b BookRequestBody) GetOriginalLanguage() string {
+	return b.OriginalLanguage
+}
+
+func (b BookRequestBody) GetLocation() string {
+	return b.Location
+}
+
 //TODO Thing more about this, it makes no sense
 func (b BookRequestBody) GetUser() *model.User {
 	return nil
 }
 
-var (
-	memoryBookInteractor   usecase.BookInteractor
-	postgresBookInteractor usecase.BookInteractor
-)
+// GetSupplierID ignores the supplier sent on the request, it's only set on
+// the responses, the books are supplied with PUT /books/{id}/supplier
+func (b BookRequestBody) GetSupplierID() string {
+	return ""
+}
+
+func (b BookRequestBody) GetCreatedAt() time.Time {
+	return time.Time{}
+}
 
-func init() {
-	memoryBookInteractor = usecase.NewBookInteractor(
-		*memory.NewBookController(),
-		service.NewBookService(memory.NewBookController()),
-	)
-	db := postgres.NewClient(os.Getenv("POSTGRES_HOST"), os.Getenv("POSTGRES_PORT"), os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_DATABASE"), os.Getenv("POSTGRES_PASSWORD")).Connect().DB()
-	postgresBookInteractor = usecase.NewBookInteractor(
-		*postgres.NewBookController(db),
-		service.NewBookService(postgres.NewBookController(db)),
-	)
+func (b BookRequestBody) GetUpdatedAt() time.Time {
+	return time.Time{}
 }
 
 func ListAllBooks(w http.ResponseWriter, r *http.Request) {
-	var err error
 	var books []model.Book
 
-	switch r.Header.Get(customPersistenceHeader) {
-	case "memory":
-		books, err = memoryBookInteractor.ListBooks()
-	case "postgres":
-		books, err = postgresBookInteractor.ListBooks()
-	default:
-		err = fmt.Errorf("Persistence type not available")
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		if language := r.URL.Query().Get("language"); language != "" {
+			books, err = interactor.ListBooksByLanguage(language)
+		} else {
+			books, err = interactor.ListBooks()
+		}
 	}
 
 	if err != nil {
 		log.Printf("Error while try to find all the books: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
 	booksResult := make([]BookRequestBody, len(books))
 	for i, book := range books {
-		booksResult[i] = BookRequestBody{
-			ID:    book.GetID(),
-			Title: book.GetTitle(),
-			ISBN:  book.GetISBN(),
-			Price: book.GetPrice(),
-		}
+		booksResult[i] = toBookResult(book)
 	}
+	setBooksETag(w, r, books...)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(booksResult)
 }
 
+// ListBookLanguages returns how many books there are in each language
+func ListBookLanguages(w http.ResponseWriter, r *http.Request) {
+	var facets []*usecase.LanguageFacet
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		facets, err = interactor.LanguageFacets()
+	}
+	if err != nil {
+		log.Printf("Error while try to count the books per language: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(facets)
+}
+
 func CreateBook(w http.ResponseWriter, r *http.Request) {
-	var err error
 	bookRequest := &BookRequestBody{}
 	json.NewDecoder(r.Body).Decode(bookRequest)
 	defer r.Body.Close()
 
-	switch r.Header.Get(customPersistenceHeader) {
-	case "memory":
-		err = memoryBookInteractor.RegisterBook(bookRequest)
-	case "postgres":
-		err = postgresBookInteractor.RegisterBook(bookRequest)
-	default:
-		err = fmt.Errorf("Persistence type not available")
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		err = interactor.RegisterBook(bookRequest)
 	}
 	if err != nil {
 		log.Printf("Error while try to register a new book: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
+	invalidateCaches(r)
 	w.WriteHeader(http.StatusCreated)
 }
 
 func RemoveBook(w http.ResponseWriter, r *http.Request) {
-	var err error
-
-	switch r.Header.Get(customPersistenceHeader) {
-	case "memory":
-		err = memoryBookInteractor.RemoveBook(mux.Vars(r)["id"])
-	case "postgres":
-		err = postgresBookInteractor.RemoveBook(mux.Vars(r)["id"])
-	default:
-		err = fmt.Errorf("Persistence type not available")
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		err = interactor.RemoveBook(mux.Vars(r)["id"])
 	}
 
 	if err != nil {
 		log.Printf("Error while try to remove a book: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
+		return
+	}
+	invalidateCaches(r)
+	w.WriteHeader(http.StatusOK)
+}
+
+type LocationRequestBody struct {
+	Location string `json:"location"`
+}
+
+func MoveBook(w http.ResponseWriter, r *http.Request) {
+	locationRequest := &LocationRequestBody{}
+	json.NewDecoder(r.Body).Decode(locationRequest)
+	defer r.Body.Close()
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		err = interactor.MoveBook(mux.Vars(r)["id"], locationRequest.Location)
+	}
+	if err != nil {
+		log.Printf("Error while try to move a book: %v", err)
+		writeError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
 
 func FindBookByID(w http.ResponseWriter, r *http.Request) {
-	var err error
+	findBook(w, r, bookInteractorFor)
+}
+
+// FindPublicBook works like FindBookByID on the public persistence, it's the
+// page the feed items link to
+func FindPublicBook(w http.ResponseWriter, r *http.Request) {
+	findBook(w, r, publicBookInteractorFor)
+}
+
+func findBook(w http.ResponseWriter, r *http.Request, interactorFor func(*http.Request) (usecase.BookInteractor, error)) {
 	var book model.Book
 
-	switch r.Header.Get(customPersistenceHeader) {
-	case "memory":
-		book, err = memoryBookInteractor.FindByID(mux.Vars(r)["id"])
-	case "postgres":
-		book, err = postgresBookInteractor.FindByID(mux.Vars(r)["id"])
-	default:
-		err = fmt.Errorf("Persistence type not available")
+	interactor, err := interactorFor(r)
+	if err == nil {
+		book, err = interactor.FindByID(mux.Vars(r)["id"])
 	}
 
 	if err != nil {
 		log.Printf("Error trying to find a book: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	} else if book == nil {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	setBooksETag(w, r, book)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(&BookRequestBody{
-		ID:    book.GetID(),
-		Title: book.GetTitle(),
-		ISBN:  book.GetISBN(),
-		Price: book.GetPrice(),
+	json.NewEncoder(w).Encode(toBookResult(book))
+}
+
+type BookChangeResult struct {
+	ChangedAt time.Time                    `json:"changed_at"`
+	Changes   map[string]model.FieldChange `json:"changes"`
+}
+
+// BookHistory returns the changes made to a book, the oldest first
+func BookHistory(w http.ResponseWriter, r *http.Request) {
+	var history []*model.BookChange
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		history, err = interactor.BookHistory(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error trying to find the history of a book: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	historyResult := make([]BookChangeResult, len(history))
+	for i, change := range history {
+		historyResult[i] = BookChangeResult{
+			ChangedAt: change.GetChangedAt(),
+			Changes:   change.GetFields(),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(historyResult)
+}
+
+type BulkDeleteRequestBody struct {
+	IDs      []string `json:"ids"`
+	Language string   `json:"language"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+type BulkDeleteResponseBody struct {
+	DryRun   bool              `json:"dry_run"`
+	Removed  []BookRequestBody `json:"removed"`
+	Rented   []BookRequestBody `json:"rented"`
+	NotFound []string          `json:"not_found"`
+}
+
+// BulkRemoveBooks removes the books selected by id or language, with dry_run
+// it only reports the books that would be removed
+func BulkRemoveBooks(w http.ResponseWriter, r *http.Request) {
+	var removal *usecase.BulkRemoval
+
+	bulkRequest := &BulkDeleteRequestBody{}
+	json.NewDecoder(r.Body).Decode(bulkRequest)
+	defer r.Body.Close()
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		removal, err = interactor.BulkRemoveBooks(usecase.BulkRemovalCriteria{
+			IDs:      bulkRequest.IDs,
+			Language: bulkRequest.Language,
+		}, bulkRequest.DryRun)
+	}
+	if err != nil {
+		log.Printf("Error while try to remove the books: %v", err)
+		writeError(w, err)
+		return
+	}
+	if !removal.DryRun {
+		invalidateCaches(r)
+	}
+	notFound := removal.NotFound
+	if notFound == nil {
+		notFound = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(&BulkDeleteResponseBody{
+		DryRun:   removal.DryRun,
+		Removed:  toBookResults(removal.Removed),
+		Rented:   toBookResults(removal.Rented),
+		NotFound: notFound,
 	})
 }
+
+func toBookResults(books []model.Book) []BookRequestBody {
+	results := make([]BookRequestBody, len(books))
+	for i, book := range books {
+		results[i] = toBookResult(book)
+	}
+	return results
+}
+
+func toBookResult(book model.Book) BookRequestBody {
+	return BookRequestBody{
+		ID:               book.GetID(),
+		Title:            book.GetTitle(),
+		ISBN:             book.GetISBN(),
+		Price:            book.GetPrice(),
+		Language:         book.GetLanguage(),
+		OriginalLanguage: book.GetOriginalLanguage(),
+		Location:         book.GetLocation(),
+		SupplierID:       book.GetSupplierID(),
+	}
+}
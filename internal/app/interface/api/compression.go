@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// bufferedResponseWriter keeps the whole response in memory so we can decide
+// if it's worth to compress it once the handler has finished
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+// compressHandler compresses the response with gzip when the client accepts
+// it and the response is at least minSize bytes
+func compressHandler(minSize int, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next(w, r)
+			return
+		}
+
+		bw := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(bw, r)
+
+		if bw.buf.Len() < minSize {
+			w.WriteHeader(bw.status)
+			w.Write(bw.buf.Bytes())
+			return
+		}
+
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		gz.Write(bw.buf.Bytes())
+		gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+		w.WriteHeader(bw.status)
+		w.Write(compressed.Bytes())
+	}
+}
+
+// acceptsGzip checks the Accept-Encoding header, taking into account that the
+// client can explicitly refuse gzip with q=0
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		encoding := strings.TrimSpace(fields[0])
+		if encoding != "gzip" && encoding != "*" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
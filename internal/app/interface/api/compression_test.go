@@ -0,0 +1,69 @@
+package api
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func fakeListHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}
+}
+
+func TestCompressLargeResponse(t *testing.T) {
+	body := strings.Repeat("a", 100)
+	req := httptest.NewRequest("GET", "/books", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rec := httptest.NewRecorder()
+
+	compressHandler(10, fakeListHandler(body))(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Should be compressed with gzip but got encoding %s", rec.Header().Get("Content-Encoding"))
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	res, _ := ioutil.ReadAll(gz)
+	if string(res) != body {
+		t.Errorf("The uncompressed body should be the original one but got %s", string(res))
+	}
+}
+
+func TestNotCompressSmallResponse(t *testing.T) {
+	req := httptest.NewRequest("GET", "/books", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	compressHandler(1024, fakeListHandler("[]"))(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Shouldn't be compressed but got encoding %s", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Body.String() != "[]" {
+		t.Errorf("Should get the original body but got %s", rec.Body.String())
+	}
+}
+
+func TestNotCompressWhenNotAccepted(t *testing.T) {
+	body := strings.Repeat("a", 100)
+	for _, header := range []string{"", "deflate", "gzip;q=0"} {
+		req := httptest.NewRequest("GET", "/books", nil)
+		req.Header.Set("Accept-Encoding", header)
+		rec := httptest.NewRecorder()
+
+		compressHandler(10, fakeListHandler(body))(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Shouldn't be compressed for Accept-Encoding %q but got encoding %s", header, rec.Header().Get("Content-Encoding"))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+type CopyCatalogRequestBody struct {
+	ISBN  string `json:"isbn"`
+	Title string `json:"title"`
+}
+
+var (
+	copyCatalogInteractor usecase.CopyCatalogInteractor
+)
+
+// CopyCatalogBooks searches the external catalog and returns drafts of the
+// books found, ready to be reviewed and sent to CreateBook
+func CopyCatalogBooks(w http.ResponseWriter, r *http.Request) {
+	copyRequest := &CopyCatalogRequestBody{}
+	json.NewDecoder(r.Body).Decode(copyRequest)
+	defer r.Body.Close()
+
+	books, err := copyCatalogInteractor.Drafts(copyRequest.ISBN, copyRequest.Title)
+	if err != nil {
+		log.Printf("Error while try to search the external catalog: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	drafts := make([]BookRequestBody, len(books))
+	for i, book := range books {
+		drafts[i] = BookRequestBody{
+			Title:            book.GetTitle(),
+			ISBN:             book.GetISBN(),
+			Language:         book.GetLanguage(),
+			OriginalLanguage: book.GetOriginalLanguage(),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(drafts)
+}
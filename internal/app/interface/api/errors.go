@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type ErrorResponseBody struct {
+	Error string `json:"error"`
+	ID    string `json:"id,omitempty"`
+}
+
+// writeError maps the domain errors into the proper status code, any error
+// not known by the domain is treated as an internal error and its details are
+// not sent to the client
+func writeError(w http.ResponseWriter, err error) {
+	var status int
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domainerr.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, domainerr.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, domainerr.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domainerr.ErrUnavailable):
+		status = http.StatusServiceUnavailable
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body := &ErrorResponseBody{Error: err.Error()}
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) {
+		body.ID = domainErr.ResourceID()
+		if retryAfter := domainErr.RetryAfter(); retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestWriteErrorStatusCodes(t *testing.T) {
+	cases := map[int]error{
+		http.StatusNotFound:            domainerr.NotFound("not found"),
+		http.StatusConflict:            domainerr.Conflict("conflict"),
+		http.StatusBadRequest:          domainerr.Validation("validation"),
+		http.StatusForbidden:           domainerr.Forbidden("forbidden"),
+		http.StatusServiceUnavailable:  domainerr.Unavailable(time.Second, "unavailable"),
+		http.StatusInternalServerError: errors.New("unknown"),
+	}
+	for status, err := range cases {
+		rec := httptest.NewRecorder()
+		writeError(rec, err)
+		if rec.Code != status {
+			t.Errorf("Should get %d for %v but got %d", status, err, rec.Code)
+		}
+	}
+}
+
+func TestWriteErrorConflictWithID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeError(rec, domainerr.ConflictWith("existingBookID", "testIsbn already exists"))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Should get 409 but got %d", rec.Code)
+	}
+	body := &ErrorResponseBody{}
+	if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if body.ID != "existingBookID" {
+		t.Errorf("Should return the id of the existing book but got %s", body.ID)
+	}
+}
+
+func TestWriteErrorHidesInternalErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeError(rec, errors.New("pq: password authentication failed"))
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("Shouldn't return the internal error but got %s", rec.Body.String())
+	}
+}
+
+func TestWriteErrorRetryAfter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeError(rec, domainerr.Unavailable(1500*time.Millisecond, "postgres is not available"))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Should get 503 but got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("Should get a Retry-After of 2 seconds but got %s", rec.Header().Get("Retry-After"))
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+// etagHandler adds a weak ETag and replies with 304 Not Modified when the
+// client already has the same version. The handlers can set the ETag from
+// the version of what they return, like setBooksETag does, otherwise it's
+// computed from the response body
+func etagHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bw := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(bw, r)
+
+		if bw.status != http.StatusOK {
+			w.WriteHeader(bw.status)
+			w.Write(bw.buf.Bytes())
+			return
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			etag = fmt.Sprintf(`W/"%x"`, sha1.Sum(bw.buf.Bytes()))
+			w.Header().Set("ETag", etag)
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(bw.status)
+		w.Write(bw.buf.Bytes())
+	}
+}
+
+// etagMatches uses the weak comparison, so the W/ prefix is not taken into
+// account
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setBooksETag sets a weak ETag from the id and the last update of every
+// book, so it changes when any of them is changed, added or removed. The
+// query is part of it since the filters and fieldsets change the response
+func setBooksETag(w http.ResponseWriter, r *http.Request, books ...model.Book) {
+	h := sha1.New()
+	io.WriteString(h, r.URL.RawQuery)
+	for _, book := range books {
+		fmt.Fprintf(h, "\n%s:%d", book.GetID(), book.GetUpdatedAt().UnixNano())
+	}
+	w.Header().Set("ETag", fmt.Sprintf(`W/"%x"`, h.Sum(nil)))
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestETagNotModified(t *testing.T) {
+	req := httptest.NewRequest("GET", "/books", nil)
+	rec := httptest.NewRecorder()
+	etagHandler(fakeListHandler(`[{"id":"1"}]`))(rec, req)
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Should return an ETag")
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Error("Should return a Cache-Control header")
+	}
+
+	req = httptest.NewRequest("GET", "/books", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	etagHandler(fakeListHandler(`[{"id":"1"}]`))(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Should return 304 but got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Shouldn't return a body but got %s", rec.Body.String())
+	}
+}
+
+func TestETagModified(t *testing.T) {
+	req := httptest.NewRequest("GET", "/books", nil)
+	req.Header.Set("If-None-Match", `W/"outdated"`)
+	rec := httptest.NewRecorder()
+	etagHandler(fakeListHandler(`[{"id":"1"}]`))(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Should return 200 but got %d", rec.Code)
+	}
+	if rec.Body.String() != `[{"id":"1"}]` {
+		t.Errorf("Should return the body but got %s", rec.Body.String())
+	}
+}
+
+func TestETagOnlyForSuccessfulResponses(t *testing.T) {
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	req := httptest.NewRequest("GET", "/books/1", nil)
+	rec := httptest.NewRecorder()
+	etagHandler(notFound)(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Should return 404 but got %d", rec.Code)
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Errorf("Shouldn't return an ETag but got %s", rec.Header().Get("ETag"))
+	}
+}
+
+func TestETagFromTheBooksUpdate(t *testing.T) {
+	router := newTestRouter()
+	storages["memory"].Books().Save(BookRequestBody{Title: "Rayuela", ISBN: "1", Location: "A-1"})
+	books, _ := storages["memory"].Books().FindAll()
+	path := "/books/" + books[0].GetID()
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set(customPersistenceHeader, "memory")
+		req.Header.Set("If-None-Match", etag)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	etag := get(path, "").Header().Get("ETag")
+	if rec := get(path, etag); rec.Code != http.StatusNotModified {
+		t.Errorf("Should return 304 while the book is not changed but got %d", rec.Code)
+	}
+	if get("/books", "").Header().Get("ETag") == get("/books?fields=id", "").Header().Get("ETag") {
+		t.Error("The fieldsets should have their own ETag")
+	}
+
+	req := httptest.NewRequest("PUT", path+"/location", strings.NewReader(`{"location":"B-2"}`))
+	req.Header.Set(customPersistenceHeader, "memory")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if rec := get(path, etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("Should return the moved book with a new ETag but got %d and %s", rec.Code, rec.Header().Get("ETag"))
+	}
+}
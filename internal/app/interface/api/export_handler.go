@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+const (
+	signatureHeader = "X-Signature"
+)
+
+type AvailabilityExport struct {
+	GeneratedAt time.Time                   `json:"generated_at"`
+	Books       []*usecase.BookAvailability `json:"books"`
+}
+
+// exportAvailabilityHandler returns a snapshot with the availability of all
+// the books for the consortium partners, as JSON or as CSV with ?format=csv
+// or Accept: text/csv. With a signing key the body is signed with HMAC-SHA256
+// on the X-Signature header, so the partners can check where it comes from
+func exportAvailabilityHandler(signingKey string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var availability []*usecase.BookAvailability
+
+		interactor, err := publicBookInteractorFor(r)
+		if err == nil {
+			availability, err = interactor.Availability()
+		}
+		if err != nil {
+			log.Printf("Error while try to export the availability: %v", err)
+			writeError(w, err)
+			return
+		}
+
+		export := AvailabilityExport{GeneratedAt: time.Now().UTC(), Books: availability}
+		var body bytes.Buffer
+		if wantsCSV(r) {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="availability-`+export.GeneratedAt.Format("20060102")+`.csv"`)
+			err = writeAvailabilityCSV(&body, export)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(&body).Encode(export)
+		}
+		if err != nil {
+			log.Printf("Error while try to encode the availability export: %v", err)
+			writeError(w, err)
+			return
+		}
+
+		if signingKey != "" {
+			mac := hmac.New(sha256.New, []byte(signingKey))
+			mac.Write(body.Bytes())
+			w.Header().Set(signatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(body.Bytes())
+	}
+}
+
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+// writeAvailabilityCSV writes one row per book after the header, the
+// snapshot time is on every row so the files can be merged
+func writeAvailabilityCSV(buf *bytes.Buffer, export AvailabilityExport) error {
+	writer := csv.NewWriter(buf)
+	writer.Write([]string{"id", "title", "isbn", "location", "status", "generated_at"})
+	generatedAt := export.GeneratedAt.Format(time.RFC3339)
+	for _, book := range export.Books {
+		writer.Write([]string{book.ID, book.Title, book.ISBN, book.Location, book.Status, generatedAt})
+	}
+	writer.Flush()
+	return writer.Error()
+}
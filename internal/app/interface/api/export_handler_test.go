@@ -0,0 +1,93 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/config"
+)
+
+func newExportRouter() http.Handler {
+	bookController := memory.NewBookController()
+	bookController.Load(
+		BookRequestBody{ID: "1", Title: "Rayuela", ISBN: "1", Location: "A-1"},
+		rentedBook{BookRequestBody{ID: "2", Title: "Ficciones", ISBN: "2"}},
+	)
+	setupInteractors(map[string]repository.Storage{
+		"memory": storageWithBooks{Storage: memory.NewStorage(), books: bookController},
+	}, time.Minute)
+	return newRouter(&config.Config{CompressionMinSize: 1024, ExportSigningKey: "secret"})
+}
+
+// rentedBook is rented by a user, the request bodies never have one
+type rentedBook struct {
+	BookRequestBody
+}
+
+func (b rentedBook) GetUser() *model.User {
+	return model.NewUser("u1", "test@test.com", "Test", "User")
+}
+
+type storageWithBooks struct {
+	repository.Storage
+	books repository.BookRepository
+}
+
+func (s storageWithBooks) Books() repository.BookRepository {
+	return s.books
+}
+
+func TestExportAvailability(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newExportRouter().ServeHTTP(rec, httptest.NewRequest("GET", "/exports/availability", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Should get 200 but got %d", rec.Code)
+	}
+	var export AvailabilityExport
+	if err := json.Unmarshal(rec.Body.Bytes(), &export); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	status := map[string]string{}
+	for _, book := range export.Books {
+		status[book.ID] = book.Status
+	}
+	if status["1"] != usecase.StatusAvailable || status["2"] != usecase.StatusRented {
+		t.Errorf("The rented book shouldn't be available but got %v", status)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(rec.Body.Bytes())
+	if rec.Header().Get(signatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("The export should be signed but got %q", rec.Header().Get(signatureHeader))
+	}
+}
+
+func TestExportAvailabilityCSV(t *testing.T) {
+	req := httptest.NewRequest("GET", "/exports/availability", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec := httptest.NewRecorder()
+	newExportRouter().ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Errorf("Should be a CSV file but got %s", rec.Header().Get("Content-Type"))
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "id" || rows[0][4] != "status" {
+		t.Errorf("Should get the header and one row per book but got %v", rows)
+	}
+}
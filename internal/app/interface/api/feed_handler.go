@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// NewArrivalsFeed returns an RSS feed with the books added on the last 30
+// days, meant to be syndicated on the library website
+func NewArrivalsFeed(w http.ResponseWriter, r *http.Request) {
+	var books []model.Book
+
+	interactor, err := feedInteractorFor(r)
+	if err == nil {
+		books, err = interactor.NewArrivals()
+	}
+	if err != nil {
+		log.Printf("Error while try to list the new arrivals: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	baseURL := requestBaseURL(r)
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       "Librarium new arrivals",
+			Link:        baseURL + "/public/new-arrivals.xml",
+			Description: "Books added to the catalog on the last 30 days",
+			Items:       make([]rssItem, len(books)),
+		},
+	}
+	for i, book := range books {
+		link := fmt.Sprintf("%s/public/books/%s", baseURL, book.GetID())
+		feed.Channel.Items[i] = rssItem{
+			Title:       book.GetTitle(),
+			Link:        link,
+			Description: fmt.Sprintf("ISBN %s", book.GetISBN()),
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     book.GetCreatedAt().UTC().Format(time.RFC1123Z),
+		}
+	}
+	if len(books) > 0 {
+		feed.Channel.LastBuildDate = books[0].GetCreatedAt().UTC().Format(time.RFC1123Z)
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(feed)
+}
+
+// requestBaseURL builds the absolute URL the client used, the feed readers
+// need absolute links
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewArrivalsFeed(t *testing.T) {
+	router := newTestRouter()
+	storages["memory"].Books().Save(BookRequestBody{Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+
+	// The feed readers can't send the persistence header
+	req := httptest.NewRequest("GET", "http://library.test/public/new-arrivals.xml", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Should get 200 but got %d", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/rss+xml") {
+		t.Errorf("Should be an RSS feed but got %s", rec.Header().Get("Content-Type"))
+	}
+	var feed rssFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Title != "Test Title" {
+		t.Fatalf("Should list the new book but got %v", feed.Channel.Items)
+	}
+	if !strings.HasPrefix(feed.Channel.Items[0].Link, "http://library.test/public/books/") {
+		t.Errorf("Should link to the book with an absolute URL but got %s", feed.Channel.Items[0].Link)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("The feed should have an ETag, so the readers can check if it changed")
+	}
+
+	// The readers follow the links without the persistence header either
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", feed.Channel.Items[0].Link, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Should get 200 following the item link but got %d", rec.Code)
+	}
+}
+
+func TestNewArrivalsFeedAfterCreatingABook(t *testing.T) {
+	router := newTestRouter()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/public/new-arrivals.xml", nil))
+
+	req := httptest.NewRequest("POST", "/books", strings.NewReader(`{"title":"Rayuela","isbn":"1"}`))
+	req.Header.Set(customPersistenceHeader, "memory")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/public/new-arrivals.xml", nil))
+	var feed rssFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Errorf("Should list the book just created instead of the cached feed but got %v", feed.Channel.Items)
+	}
+}
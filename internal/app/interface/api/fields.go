@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// fieldsHandler supports sparse fieldsets on the list endpoints, a request
+// with ?fields=id,title only gets those fields of every item. The field names
+// are case insensitive and the ones not known are ignored
+func fieldsHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fields := requestedFields(r)
+		if len(fields) == 0 {
+			next(w, r)
+			return
+		}
+
+		bw := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(bw, r)
+
+		var items []map[string]interface{}
+		if bw.status != http.StatusOK || json.Unmarshal(bw.buf.Bytes(), &items) != nil {
+			w.WriteHeader(bw.status)
+			w.Write(bw.buf.Bytes())
+			return
+		}
+		for _, item := range items {
+			for key := range item {
+				if !fields[strings.ToLower(key)] {
+					delete(item, key)
+				}
+			}
+		}
+		w.WriteHeader(bw.status)
+		json.NewEncoder(w).Encode(items)
+	}
+}
+
+func requestedFields(r *http.Request) map[string]bool {
+	fields := map[string]bool{}
+	for _, field := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			fields[field] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFieldsSelection(t *testing.T) {
+	req := httptest.NewRequest("GET", "/books?fields=id,TITLE", nil)
+	rec := httptest.NewRecorder()
+	fieldsHandler(fakeListHandler(`[{"id":"1","title":"Test Title","isbn":"testIsbn","price":34.4}]`))(rec, req)
+
+	var items []map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Should be a list with only one item but got %d items", len(items))
+	}
+	if len(items[0]) != 2 || items[0]["id"] != "1" || items[0]["title"] != "Test Title" {
+		t.Errorf("Should only get the id and the title but got %v", items[0])
+	}
+}
+
+func TestWithoutFieldsSelection(t *testing.T) {
+	body := `[{"id":"1","title":"Test Title"}]`
+	req := httptest.NewRequest("GET", "/books", nil)
+	rec := httptest.NewRecorder()
+	fieldsHandler(fakeListHandler(body))(rec, req)
+
+	if rec.Body.String() != body {
+		t.Errorf("Should get the whole body but got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	allowedMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+)
+
+// optionsHandler answers the OPTIONS requests with the methods registered on
+// the router for the requested path
+func optionsHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allow []string
+		for _, method := range allowedMethods {
+			req := r.Clone(r.Context())
+			req.Method = method
+			var match mux.RouteMatch
+			if router.Match(req, &match) && match.MatchErr == nil {
+				allow = append(allow, method)
+			}
+		}
+		if len(allow) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Allow", strings.Join(append(allow, "OPTIONS"), ", "))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
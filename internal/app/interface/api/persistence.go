@@ -0,0 +1,193 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/counting"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+const (
+	customPersistenceHeader = "X-Persistence-Type"
+)
+
+var (
+	storages             map[string]repository.Storage
+	userInteractors      map[string]usecase.UserInteractor
+	bookInteractors      map[string]usecase.BookInteractor
+	tagInteractors       map[string]usecase.TagInteractor
+	supplierInteractors  map[string]usecase.SupplierInteractor
+	stocktakeInteractors map[string]usecase.StocktakeInteractor
+	statsInteractors     map[string]usecase.StatsInteractor
+	feedInteractors      map[string]usecase.FeedInteractor
+	// publicPersistence is used by the public endpoints, like the feeds,
+	// since their clients can't send the persistence header
+	publicPersistence = "memory"
+	// statsNotifiers tell the other instances sharing the storage that their
+	// cached stats and feeds are stale
+	statsNotifiers = map[string]func() error{}
+)
+
+// setupInteractors builds the interactors for each one of the storages, the
+// key is the value clients send on the persistence header
+func setupInteractors(available map[string]repository.Storage, cacheTTL time.Duration) {
+	storages = available
+	userInteractors = map[string]usecase.UserInteractor{}
+	bookInteractors = map[string]usecase.BookInteractor{}
+	tagInteractors = map[string]usecase.TagInteractor{}
+	supplierInteractors = map[string]usecase.SupplierInteractor{}
+	stocktakeInteractors = map[string]usecase.StocktakeInteractor{}
+	statsInteractors = map[string]usecase.StatsInteractor{}
+	feedInteractors = map[string]usecase.FeedInteractor{}
+	for name, storage := range storages {
+		userInteractors[name] = usecase.NewUserInteractor(
+			storage.Users(),
+			service.NewUserService(storage.Users()),
+		)
+		bookInteractors[name] = usecase.NewBookInteractor(
+			storage.Books(),
+			service.NewBookService(storage.Books()),
+		)
+		tagInteractors[name] = usecase.NewTagInteractor(storage.Tags(), storage.Books())
+		supplierInteractors[name] = usecase.NewSupplierInteractor(storage.Suppliers(), storage.Books())
+		stocktakeInteractors[name] = usecase.NewStocktakeInteractor(storage.Stocktakes(), storage.Books())
+		statsInteractors[name] = usecase.NewStatsInteractor(storage.Users(), storage.Books(), cacheTTL)
+		feedInteractors[name] = usecase.NewFeedInteractor(storage.Books(), cacheTTL)
+	}
+}
+
+func userInteractorFor(r *http.Request) (usecase.UserInteractor, error) {
+	interactor, ok := userInteractors[r.Header.Get(customPersistenceHeader)]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, r.Header.Get(customPersistenceHeader)); storage != nil {
+		return usecase.NewUserInteractor(storage.Users(), service.NewUserService(storage.Users())), nil
+	}
+	return interactor, nil
+}
+
+func bookInteractorFor(r *http.Request) (usecase.BookInteractor, error) {
+	return bookInteractorOn(r, r.Header.Get(customPersistenceHeader))
+}
+
+// publicBookInteractorFor uses the public persistence when the request
+// doesn't choose one
+func publicBookInteractorFor(r *http.Request) (usecase.BookInteractor, error) {
+	return bookInteractorOn(r, publicPersistenceFor(r))
+}
+
+func bookInteractorOn(r *http.Request, persistence string) (usecase.BookInteractor, error) {
+	interactor, ok := bookInteractors[persistence]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, persistence); storage != nil {
+		return usecase.NewBookInteractor(storage.Books(), service.NewBookService(storage.Books())), nil
+	}
+	return interactor, nil
+}
+
+func tagInteractorFor(r *http.Request) (usecase.TagInteractor, error) {
+	interactor, ok := tagInteractors[r.Header.Get(customPersistenceHeader)]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, r.Header.Get(customPersistenceHeader)); storage != nil {
+		return usecase.NewTagInteractor(storage.Tags(), storage.Books()), nil
+	}
+	return interactor, nil
+}
+
+func supplierInteractorFor(r *http.Request) (usecase.SupplierInteractor, error) {
+	interactor, ok := supplierInteractors[r.Header.Get(customPersistenceHeader)]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, r.Header.Get(customPersistenceHeader)); storage != nil {
+		return usecase.NewSupplierInteractor(storage.Suppliers(), storage.Books()), nil
+	}
+	return interactor, nil
+}
+
+func stocktakeInteractorFor(r *http.Request) (usecase.StocktakeInteractor, error) {
+	interactor, ok := stocktakeInteractors[r.Header.Get(customPersistenceHeader)]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, r.Header.Get(customPersistenceHeader)); storage != nil {
+		return usecase.NewStocktakeInteractor(storage.Stocktakes(), storage.Books()), nil
+	}
+	return interactor, nil
+}
+
+// countingStorageFor wraps the storage with the counter of the request, the
+// interactors are cheap to build so a new one is built for the request, the
+// ones keeping a cache share it with the new one
+func countingStorageFor(r *http.Request, persistence string) repository.Storage {
+	counter := storageCounterFrom(r)
+	if counter == nil {
+		return nil
+	}
+	return counting.NewStorage(storages[persistence], counter)
+}
+
+func statsInteractorFor(r *http.Request) (usecase.StatsInteractor, error) {
+	interactor, ok := statsInteractors[r.Header.Get(customPersistenceHeader)]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, r.Header.Get(customPersistenceHeader)); storage != nil {
+		return interactor.WithRepositories(storage.Users(), storage.Books()), nil
+	}
+	return interactor, nil
+}
+
+// feedInteractorFor uses the public persistence when the request doesn't
+// choose one
+func feedInteractorFor(r *http.Request) (usecase.FeedInteractor, error) {
+	persistence := publicPersistenceFor(r)
+	interactor, ok := feedInteractors[persistence]
+	if !ok {
+		return nil, domainerr.Validation("Persistence type not available")
+	}
+	if storage := countingStorageFor(r, persistence); storage != nil {
+		return interactor.WithRepository(storage.Books()), nil
+	}
+	return interactor, nil
+}
+
+func publicPersistenceFor(r *http.Request) string {
+	if persistence := r.Header.Get(customPersistenceHeader); persistence != "" {
+		return persistence
+	}
+	return publicPersistence
+}
+
+// invalidateCaches drops the cached stats and feed of the storage used on the
+// request, it's called after every change on the users or books
+func invalidateCaches(r *http.Request) {
+	persistence := r.Header.Get(customPersistenceHeader)
+	dropCaches(persistence)
+	if notify, ok := statsNotifiers[persistence]; ok {
+		if err := notify(); err != nil {
+			log.Printf("Error notifying the stats invalidation to the other instances: %v", err)
+		}
+	}
+}
+
+// dropCaches drops the cached stats and feed of the storage, without telling
+// the other instances
+func dropCaches(persistence string) {
+	if interactor, ok := statsInteractors[persistence]; ok {
+		interactor.Invalidate()
+	}
+	if interactor, ok := feedInteractors[persistence]; ok {
+		interactor.Invalidate()
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestInteractorForPersistenceHeader(t *testing.T) {
+	setupInteractors(map[string]repository.Storage{
+		"memory": memory.NewStorage(),
+	}, time.Minute)
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	if _, err := userInteractorFor(req); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if _, err := bookInteractorFor(req); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+
+	req.Header.Set(customPersistenceHeader, "sqlite")
+	if _, err := userInteractorFor(req); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error for a storage not configured but got %v", err)
+	}
+}
+
+func TestInvalidateCachesNotifiesOtherInstances(t *testing.T) {
+	setupInteractors(map[string]repository.Storage{
+		"memory": memory.NewStorage(),
+	}, time.Minute)
+	notified := 0
+	statsNotifiers["memory"] = func() error {
+		notified++
+		return nil
+	}
+	defer delete(statsNotifiers, "memory")
+
+	req := httptest.NewRequest("POST", "/books", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	invalidateCaches(req)
+	if notified != 1 {
+		t.Errorf("Should notify the invalidation once but got %d", notified)
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+var (
+	ready int32
+)
+
+// SetReady marks the application as ready to accept traffic, it should be
+// set once the databases are checked and migrated, and unset when shutting
+// down so the load balancer stops sending requests
+func SetReady(isReady bool) {
+	if isReady {
+		atomic.StoreInt32(&ready, 1)
+	} else {
+		atomic.StoreInt32(&ready, 0)
+	}
+}
+
+// Readiness replies 200 when the application is ready and 503 otherwise
+func Readiness(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&ready) == 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
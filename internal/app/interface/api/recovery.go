@@ -0,0 +1,33 @@
+package api
+
+import (
+	"encoding/json"
+	"expvar"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+var (
+	recoveredPanics = expvar.NewInt("recovered_panics")
+)
+
+// recoveryMiddleware stops a panic on any handler from killing the request
+// with an empty response, it logs the stack and replies with a JSON 500
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				recoveredPanics.Add(1)
+				log.Printf("Recovered from a panic on %s %s: %v\n%s", r.Method, r.URL.Path, err, debug.Stack())
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(&ErrorResponseBody{Error: "Internal server error"})
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/config"
+)
+
+func TestRecoverFromPanic(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var books map[string]string
+		books["nil map"] = "panics"
+	})
+	before := recoveredPanics.Value()
+	rec := httptest.NewRecorder()
+	recoveryMiddleware(panicking).ServeHTTP(rec, httptest.NewRequest("GET", "/books", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Should get 500 but got %d", rec.Code)
+	}
+	body := &ErrorResponseBody{}
+	if err := json.NewDecoder(rec.Body).Decode(body); err != nil || body.Error == "" {
+		t.Errorf("Should get a JSON error but got %s", rec.Body.String())
+	}
+	if recoveredPanics.Value() != before+1 {
+		t.Errorf("Should count the recovered panic but got %d", recoveredPanics.Value())
+	}
+}
+
+func TestRecoveryWithoutPanic(t *testing.T) {
+	rec := httptest.NewRecorder()
+	recoveryMiddleware(fakeListHandler("[]")).ServeHTTP(rec, httptest.NewRequest("GET", "/books", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]" {
+		t.Errorf("Should get the handler response but got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRouterRecoversFromPanic(t *testing.T) {
+	router := newTestRouter()
+	// A nil interactor makes the handler panic
+	userInteractors["memory"] = nil
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Should get 500 but got %d", rec.Code)
+	}
+}
+
+func TestRouterPublishesTheRecoveredPanics(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+	if rec.Code == http.StatusOK {
+		t.Errorf("The metrics shouldn't be served unless enabled but got %d", rec.Code)
+	}
+
+	router := newRouter(&config.Config{CompressionMinSize: 1024, DebugVars: true})
+	userInteractors["memory"] = nil
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Should get 200 but got %d", rec.Code)
+	}
+	var vars map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if panics, ok := vars["recovered_panics"].(float64); !ok || panics < 1 {
+		t.Errorf("Should publish the recovered panics but got %v", vars["recovered_panics"])
+	}
+}
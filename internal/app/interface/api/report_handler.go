@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+type DuplicatedBooksResponseBody struct {
+	Reason string                 `json:"reason"`
+	Key    string                 `json:"key"`
+	Books  []DuplicatedBookResult `json:"books"`
+}
+
+type DuplicatedBookResult struct {
+	BookRequestBody
+	Link string `json:"link"`
+}
+
+func ListDuplicatedBooks(w http.ResponseWriter, r *http.Request) {
+	var duplicates []*usecase.DuplicatedBooks
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		duplicates, err = interactor.ListDuplicates()
+	}
+	if err != nil {
+		log.Printf("Error while try to find the duplicated books: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	result := make([]DuplicatedBooksResponseBody, len(duplicates))
+	for i, group := range duplicates {
+		result[i] = DuplicatedBooksResponseBody{
+			Reason: group.Reason,
+			Key:    group.Key,
+			Books:  make([]DuplicatedBookResult, len(group.Books)),
+		}
+		for j, book := range group.Books {
+			result[i].Books[j] = DuplicatedBookResult{
+				BookRequestBody: toBookResult(book),
+				Link:            fmt.Sprintf("/books/%s", book.GetID()),
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// ListShelfBooks returns the books in the order they are on the shelves, to
+// reshelve or check them quickly
+func ListShelfBooks(w http.ResponseWriter, r *http.Request) {
+	var books []model.Book
+
+	interactor, err := bookInteractorFor(r)
+	if err == nil {
+		books, err = interactor.ShelfList()
+	}
+	if err != nil {
+		log.Printf("Error while try to build the shelf list: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toBookResults(books))
+}
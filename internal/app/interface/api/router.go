@@ -1,22 +1,133 @@
 package api
 
 import (
+	"expvar"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/breaker"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/postgres"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/sqlite"
+	"github.com/ramonmacias/librarium/internal/app/interface/sru"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/config"
 )
 
-func BuildRouter() *mux.Router {
+// BuildRouter connects to the databases and runs the migrations when enabled,
+// so the router is only returned once the application can serve traffic
+func BuildRouter(cfg *config.Config) *mux.Router {
+	storages := map[string]repository.Storage{
+		"memory": memory.NewStorage(),
+	}
+	// listenStats is only set when postgres is configured, it can't start
+	// before the stats and feed interactors exist
+	var listenStats func() error
+	if cfg.Postgres != nil {
+		log.Printf("Connecting to postgres at %s:%s", cfg.Postgres.Host, cfg.Postgres.Port)
+		pgClient := postgres.NewClient(cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Database, cfg.Postgres.Password).
+			WithReplica(cfg.Postgres.ReplicaDSN).
+			WithSlowQueryThreshold(cfg.Postgres.SlowQueryThreshold)
+		conn := pgClient.Connect()
+		if cfg.RunMigrations {
+			log.Println("Running the postgres migrations")
+			if err := conn.Migrate(); err != nil {
+				log.Panicf("Error running the migrations: %v", err)
+			}
+		}
+		publicPersistence = "postgres"
+		storages["postgres"] = breaker.NewStorage(
+			postgres.NewStorage(conn),
+			breaker.New("postgres", cfg.Breaker.Threshold, cfg.Breaker.Timeout),
+		)
+		statsNotifiers["postgres"] = func() error {
+			return conn.Notify(postgres.StatsChannel)
+		}
+		listenStats = func() error {
+			return pgClient.Listen(postgres.StatsChannel, func() { dropCaches("postgres") })
+		}
+	}
+	if cfg.SQLitePath != "" {
+		log.Printf("Opening sqlite at %s", cfg.SQLitePath)
+		if cfg.Postgres == nil {
+			publicPersistence = "sqlite"
+		}
+		storages["sqlite"] = breaker.NewStorage(
+			sqlite.NewStorage(sqlite.NewClient(cfg.SQLitePath).Connect()),
+			breaker.New("sqlite", cfg.Breaker.Threshold, cfg.Breaker.Timeout),
+		)
+	}
+	setupInteractors(storages, cfg.StatsCacheTTL)
+	if listenStats != nil {
+		if err := listenStats(); err != nil {
+			log.Printf("Error listening for the stats invalidations, only the local changes will be seen: %v", err)
+		}
+	}
+	copyCatalogInteractor = usecase.NewCopyCatalogInteractor(
+		sru.NewClient(cfg.SRUURL, &http.Client{Timeout: 10 * time.Second}),
+	)
+
+	r := newRouter(cfg)
+	http.Handle("/", r)
+	return r
+}
+
+// newRouter registers all the routes, every GET route also answers HEAD
+// requests and any route answers OPTIONS with the allowed methods
+func newRouter(cfg *config.Config) *mux.Router {
 	r := mux.NewRouter()
-	r.HandleFunc("/users", ListAllUsers).Methods("GET")
+	r.Use(recoveryMiddleware)
+	if cfg.StorageCallThreshold > 0 || cfg.StorageCallHeader {
+		r.Use(storageCallsMiddleware(cfg.StorageCallThreshold, cfg.StorageCallHeader))
+	}
+	if cfg.BodyLogSampleRate > 0 {
+		r.Use(bodyLoggingMiddleware(cfg.BodyLogSampleRate))
+	}
+	r.HandleFunc("/readyz", Readiness).Methods("GET", "HEAD")
+	// The server doesn't use the default mux, so the metrics published with
+	// expvar are served here, only when enabled since they are not protected
+	if cfg.DebugVars {
+		r.Handle("/debug/vars", expvar.Handler()).Methods("GET", "HEAD")
+	}
+	r.HandleFunc("/users", compressHandler(cfg.CompressionMinSize, fieldsHandler(ListAllUsers))).Methods("GET", "HEAD")
 	r.HandleFunc("/users", CreateUser).Methods("POST")
 	r.HandleFunc("/users/{id}", RemoveUser).Methods("DELETE")
-	r.HandleFunc("/users/{id}", FindUserByID).Methods("GET")
-	r.HandleFunc("/books", ListAllBooks).Methods("GET")
+	r.HandleFunc("/users/{id}/restore", RestoreUser).Methods("POST")
+	r.HandleFunc("/users/{id}", FindUserByID).Methods("GET", "HEAD")
+	r.HandleFunc("/books", compressHandler(cfg.CompressionMinSize, etagHandler(fieldsHandler(ListAllBooks)))).Methods("GET", "HEAD")
 	r.HandleFunc("/books", CreateBook).Methods("POST")
+	r.HandleFunc("/books/languages", ListBookLanguages).Methods("GET", "HEAD")
+	r.HandleFunc("/books/bulk-delete", BulkRemoveBooks).Methods("POST")
+	r.HandleFunc("/books/copy-catalog", CopyCatalogBooks).Methods("POST")
 	r.HandleFunc("/books/{id}", RemoveBook).Methods("DELETE")
-	r.HandleFunc("/books/{id}", FindBookByID).Methods("GET")
-
-	http.Handle("/", r)
+	r.HandleFunc("/books/{id}", etagHandler(FindBookByID)).Methods("GET", "HEAD")
+	r.HandleFunc("/books/{id}/history", BookHistory).Methods("GET", "HEAD")
+	r.HandleFunc("/books/{id}/location", MoveBook).Methods("PUT")
+	r.HandleFunc("/books/{id}/supplier", SupplyBook).Methods("PUT")
+	r.HandleFunc("/books/{id}/tags", TagBook).Methods("POST")
+	r.HandleFunc("/books/{id}/tags/{tag}", UntagBook).Methods("DELETE")
+	r.HandleFunc("/stats/overview", GetStatsOverview).Methods("GET", "HEAD")
+	r.HandleFunc("/reports/books/duplicates", compressHandler(cfg.CompressionMinSize, ListDuplicatedBooks)).Methods("GET", "HEAD")
+	r.HandleFunc("/reports/books/shelf-list", compressHandler(cfg.CompressionMinSize, fieldsHandler(ListShelfBooks))).Methods("GET", "HEAD")
+	r.HandleFunc("/exports/availability", compressHandler(cfg.CompressionMinSize, exportAvailabilityHandler(cfg.ExportSigningKey))).Methods("GET", "HEAD")
+	r.HandleFunc("/public/books/{id}", etagHandler(FindPublicBook)).Methods("GET", "HEAD")
+	r.HandleFunc("/public/new-arrivals.xml", etagHandler(NewArrivalsFeed)).Methods("GET", "HEAD")
+	r.HandleFunc("/stocktakes", ListStocktakes).Methods("GET", "HEAD")
+	r.HandleFunc("/stocktakes", StartStocktake).Methods("POST")
+	r.HandleFunc("/stocktakes/{id}", FindStocktakeByID).Methods("GET", "HEAD")
+	r.HandleFunc("/stocktakes/{id}/scans", ScanStocktakeBooks).Methods("POST")
+	r.HandleFunc("/stocktakes/{id}/close", CloseStocktake).Methods("POST")
+	r.HandleFunc("/stocktakes/{id}/report", StocktakeReport).Methods("GET", "HEAD")
+	r.HandleFunc("/suppliers", ListAllSuppliers).Methods("GET", "HEAD")
+	r.HandleFunc("/suppliers", CreateSupplier).Methods("POST")
+	r.HandleFunc("/suppliers/{id}", FindSupplierByID).Methods("GET", "HEAD")
+	r.HandleFunc("/suppliers/{id}", UpdateSupplier).Methods("PUT")
+	r.HandleFunc("/suppliers/{id}", RemoveSupplier).Methods("DELETE")
+	r.HandleFunc("/suppliers/{id}/books", ListSuppliedBooks).Methods("GET", "HEAD")
+	r.HandleFunc("/tags/{tag}/books", compressHandler(cfg.CompressionMinSize, fieldsHandler(ListBooksByTag))).Methods("GET", "HEAD")
+	r.Methods("OPTIONS").HandlerFunc(optionsHandler(r))
 	return r
 }
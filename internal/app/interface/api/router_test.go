@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/config"
+)
+
+func newTestRouter() http.Handler {
+	setupInteractors(map[string]repository.Storage{
+		"memory": memory.NewStorage(),
+	}, time.Minute)
+	return newRouter(&config.Config{CompressionMinSize: 1024})
+}
+
+func TestHeadRequest(t *testing.T) {
+	req := httptest.NewRequest("HEAD", "/books", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	rec := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Should get 200 but got %d", rec.Code)
+	}
+}
+
+func TestOptionsRequest(t *testing.T) {
+	cases := map[string]string{
+		"/books":              "GET, HEAD, POST, OPTIONS",
+		"/books/1":            "GET, HEAD, DELETE, OPTIONS",
+		"/books/1/tags":       "POST, OPTIONS",
+		"/suppliers/1":        "GET, HEAD, PUT, DELETE, OPTIONS",
+		"/stocktakes/1/scans": "POST, OPTIONS",
+	}
+	router := newTestRouter()
+	for path, allow := range cases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("OPTIONS", path, nil))
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Should get 204 for %s but got %d", path, rec.Code)
+		}
+		if rec.Header().Get("Allow") != allow {
+			t.Errorf("Should allow %s for %s but got %s", allow, path, rec.Header().Get("Allow"))
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("OPTIONS", "/unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Should get 404 for an unknown path but got %d", rec.Code)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	router := newTestRouter()
+	defer SetReady(false)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Should get 503 before being ready but got %d", rec.Code)
+	}
+
+	SetReady(true)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Should get 200 once ready but got %d", rec.Code)
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+func GetStatsOverview(w http.ResponseWriter, r *http.Request) {
+	var overview *usecase.Overview
+
+	interactor, err := statsInteractorFor(r)
+	if err == nil {
+		overview, err = interactor.Overview()
+	}
+	if err != nil {
+		log.Printf("Error while try to get the stats overview: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(overview)
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+type StocktakeRequestBody struct {
+	Location string `json:"location"`
+}
+
+// ScanRequestBody is a batch of codes read on a shelf, the codes are the
+// ISBN or the id of the books
+type ScanRequestBody struct {
+	Location string   `json:"location"`
+	Codes    []string `json:"codes"`
+}
+
+type StocktakeResult struct {
+	ID        string     `json:"id"`
+	Location  string     `json:"location"`
+	StartedAt time.Time  `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+type StocktakeReportResult struct {
+	Stocktake  StocktakeResult        `json:"stocktake"`
+	Scanned    int                    `json:"scanned"`
+	Missing    []BookRequestBody      `json:"missing"`
+	Misplaced  []MisplacedBookResult  `json:"misplaced"`
+	Unexpected []UnexpectedScanResult `json:"unexpected"`
+}
+
+type MisplacedBookResult struct {
+	BookRequestBody
+	FoundAt string `json:"found_at"`
+}
+
+type UnexpectedScanResult struct {
+	Code     string `json:"code"`
+	Location string `json:"location"`
+}
+
+// StartStocktake opens a stocktake, the response has the id used to send the
+// scans and to resume it later
+func StartStocktake(w http.ResponseWriter, r *http.Request) {
+	var stocktake *model.Stocktake
+
+	stocktakeRequest := &StocktakeRequestBody{}
+	json.NewDecoder(r.Body).Decode(stocktakeRequest)
+	defer r.Body.Close()
+
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		stocktake, err = interactor.StartStocktake(stocktakeRequest.Location)
+	}
+	if err != nil {
+		log.Printf("Error while try to start a stocktake: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toStocktakeResult(stocktake))
+}
+
+func ListStocktakes(w http.ResponseWriter, r *http.Request) {
+	var stocktakes []*model.Stocktake
+
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		stocktakes, err = interactor.ListStocktakes()
+	}
+	if err != nil {
+		log.Printf("Error while try to find all the stocktakes: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	stocktakesResult := make([]StocktakeResult, len(stocktakes))
+	for i, stocktake := range stocktakes {
+		stocktakesResult[i] = toStocktakeResult(stocktake)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stocktakesResult)
+}
+
+func FindStocktakeByID(w http.ResponseWriter, r *http.Request) {
+	var stocktake *model.Stocktake
+
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		stocktake, err = interactor.FindByID(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error trying to find a stocktake: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toStocktakeResult(stocktake))
+}
+
+func ScanStocktakeBooks(w http.ResponseWriter, r *http.Request) {
+	scanRequest := &ScanRequestBody{}
+	json.NewDecoder(r.Body).Decode(scanRequest)
+	defer r.Body.Close()
+
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		err = interactor.ScanBooks(mux.Vars(r)["id"], scanRequest.Location, scanRequest.Codes)
+	}
+	if err != nil {
+		log.Printf("Error while try to scan the books of a stocktake: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func CloseStocktake(w http.ResponseWriter, r *http.Request) {
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		err = interactor.CloseStocktake(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error while try to close a stocktake: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// StocktakeReport compares the scans with the catalog, it can be asked while
+// the stocktake is open
+func StocktakeReport(w http.ResponseWriter, r *http.Request) {
+	var report *usecase.StocktakeReport
+
+	interactor, err := stocktakeInteractorFor(r)
+	if err == nil {
+		report, err = interactor.Report(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error while try to build the report of a stocktake: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	result := StocktakeReportResult{
+		Stocktake:  toStocktakeResult(report.Stocktake),
+		Scanned:    report.Scanned,
+		Missing:    toBookResults(report.Missing),
+		Misplaced:  make([]MisplacedBookResult, len(report.Misplaced)),
+		Unexpected: make([]UnexpectedScanResult, len(report.Unexpected)),
+	}
+	for i, misplaced := range report.Misplaced {
+		result.Misplaced[i] = MisplacedBookResult{
+			BookRequestBody: toBookResult(misplaced.Book),
+			FoundAt:         misplaced.FoundAt,
+		}
+	}
+	for i, scan := range report.Unexpected {
+		result.Unexpected[i] = UnexpectedScanResult{
+			Code:     scan.GetCode(),
+			Location: scan.GetLocation(),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+func toStocktakeResult(stocktake *model.Stocktake) StocktakeResult {
+	result := StocktakeResult{
+		ID:        stocktake.GetID(),
+		Location:  stocktake.GetLocation(),
+		StartedAt: stocktake.GetStartedAt(),
+	}
+	if stocktake.IsClosed() {
+		closedAt := stocktake.GetClosedAt()
+		result.ClosedAt = &closedAt
+	}
+	return result
+}
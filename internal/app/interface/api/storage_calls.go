@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/counting"
+)
+
+const (
+	storageCallsHeader = "X-Storage-Calls"
+)
+
+type storageCounterKey struct{}
+
+// storageCounterFrom returns the counter of the request, nil when the storage
+// calls are not being counted
+func storageCounterFrom(r *http.Request) *counting.Counter {
+	counter, _ := r.Context().Value(storageCounterKey{}).(*counting.Counter)
+	return counter
+}
+
+// countingResponseWriter adds the storage calls header right before the
+// response is sent, once the handler has done all its calls
+type countingResponseWriter struct {
+	http.ResponseWriter
+	counter     *counting.Counter
+	wroteHeader bool
+}
+
+func (c *countingResponseWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		c.Header().Set(storageCallsHeader, strconv.Itoa(c.counter.Count()))
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// storageCallsMiddleware counts the repository calls of every request, a call
+// can run more than one query on the databases but a growing count still
+// points to the N+1 patterns. It warns when a request makes more than
+// threshold calls, zero disables the warning, and with header the count is
+// sent back on the X-Storage-Calls header
+func storageCallsMiddleware(threshold int, header bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counter := &counting.Counter{}
+			r = r.WithContext(context.WithValue(r.Context(), storageCounterKey{}, counter))
+			if header {
+				w = &countingResponseWriter{ResponseWriter: w, counter: counter}
+			}
+			next.ServeHTTP(w, r)
+
+			if threshold > 0 && counter.Count() > threshold {
+				log.Printf("%s %s made %d storage calls, more than the %d expected, look for N+1 patterns", r.Method, r.URL.Path, counter.Count(), threshold)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/config"
+)
+
+func TestStorageCallsHeader(t *testing.T) {
+	setupInteractors(map[string]repository.Storage{
+		"memory": memory.NewStorage(),
+	}, time.Minute)
+	router := newRouter(&config.Config{CompressionMinSize: 1024, StorageCallHeader: true})
+
+	for _, book := range []string{`{"title":"Rayuela","isbn":"1"}`, `{"title":"Ficciones","isbn":"2"}`} {
+		req := httptest.NewRequest("POST", "/books", strings.NewReader(book))
+		req.Header.Set(customPersistenceHeader, "memory")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req := httptest.NewRequest("GET", "/books", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Header().Get(storageCallsHeader) != "1" {
+		t.Errorf("Listing the books should make 1 storage call but got %s", rec.Header().Get(storageCallsHeader))
+	}
+
+	req = httptest.NewRequest("GET", "/reports/books/shelf-list", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Header().Get(storageCallsHeader) != "1" {
+		t.Errorf("The header should also be sent on compressed responses but got %q", rec.Header().Get(storageCallsHeader))
+	}
+
+	req = httptest.NewRequest("GET", "/stats/overview", nil)
+	req.Header.Set(customPersistenceHeader, "memory")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Header().Get(storageCallsHeader) != "2" {
+		t.Errorf("Counting the users and books should make 2 storage calls but got %s", rec.Header().Get(storageCallsHeader))
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/public/new-arrivals.xml", nil))
+	if rec.Header().Get(storageCallsHeader) != "1" {
+		t.Errorf("The feed should make 1 storage call but got %s", rec.Header().Get(storageCallsHeader))
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+type SupplierRequestBody struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Contact    string   `json:"contact"`
+	Categories []string `json:"categories"`
+}
+
+type SupplyRequestBody struct {
+	SupplierID string `json:"supplier_id"`
+}
+
+func ListAllSuppliers(w http.ResponseWriter, r *http.Request) {
+	var suppliers []*model.Supplier
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		suppliers, err = interactor.ListSuppliers()
+	}
+	if err != nil {
+		log.Printf("Error while try to find all the suppliers: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	suppliersResult := make([]SupplierRequestBody, len(suppliers))
+	for i, supplier := range suppliers {
+		suppliersResult[i] = toSupplierResult(supplier)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(suppliersResult)
+}
+
+func CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierRequest := &SupplierRequestBody{}
+	json.NewDecoder(r.Body).Decode(supplierRequest)
+	defer r.Body.Close()
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		err = interactor.RegisterSupplier(model.NewSupplier("", supplierRequest.Name, supplierRequest.Contact, supplierRequest.Categories))
+	}
+	if err != nil {
+		log.Printf("Error while try to register a new supplier: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func FindSupplierByID(w http.ResponseWriter, r *http.Request) {
+	var supplier *model.Supplier
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		supplier, err = interactor.FindByID(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error trying to find a supplier: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toSupplierResult(supplier))
+}
+
+func UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierRequest := &SupplierRequestBody{}
+	json.NewDecoder(r.Body).Decode(supplierRequest)
+	defer r.Body.Close()
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		err = interactor.UpdateSupplier(model.NewSupplier(mux.Vars(r)["id"], supplierRequest.Name, supplierRequest.Contact, supplierRequest.Categories))
+	}
+	if err != nil {
+		log.Printf("Error while try to update a supplier: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func RemoveSupplier(w http.ResponseWriter, r *http.Request) {
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		err = interactor.RemoveSupplier(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error removing a supplier: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func ListSuppliedBooks(w http.ResponseWriter, r *http.Request) {
+	var books []model.Book
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		books, err = interactor.ListSuppliedBooks(mux.Vars(r)["id"])
+	}
+	if err != nil {
+		log.Printf("Error while try to find the books of a supplier: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toBookResults(books))
+}
+
+// SupplyBook sets the supplier of a book, an empty supplier_id removes it
+func SupplyBook(w http.ResponseWriter, r *http.Request) {
+	supplyRequest := &SupplyRequestBody{}
+	json.NewDecoder(r.Body).Decode(supplyRequest)
+	defer r.Body.Close()
+
+	interactor, err := supplierInteractorFor(r)
+	if err == nil {
+		err = interactor.SupplyBook(mux.Vars(r)["id"], supplyRequest.SupplierID)
+	}
+	if err != nil {
+		log.Printf("Error while try to set the supplier of a book: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func toSupplierResult(supplier *model.Supplier) SupplierRequestBody {
+	categories := supplier.GetCategories()
+	if categories == nil {
+		categories = []string{}
+	}
+	return SupplierRequestBody{
+		ID:         supplier.GetID(),
+		Name:       supplier.GetName(),
+		Contact:    supplier.GetContact(),
+		Categories: categories,
+	}
+}
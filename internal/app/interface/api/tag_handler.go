@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+type TagRequestBody struct {
+	Tag string `json:"tag"`
+}
+
+func TagBook(w http.ResponseWriter, r *http.Request) {
+	tagRequest := &TagRequestBody{}
+	json.NewDecoder(r.Body).Decode(tagRequest)
+	defer r.Body.Close()
+
+	interactor, err := tagInteractorFor(r)
+	if err == nil {
+		err = interactor.TagBook(mux.Vars(r)["id"], tagRequest.Tag)
+	}
+	if err != nil {
+		log.Printf("Error while try to tag a book: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func UntagBook(w http.ResponseWriter, r *http.Request) {
+	interactor, err := tagInteractorFor(r)
+	if err == nil {
+		err = interactor.UntagBook(mux.Vars(r)["id"], mux.Vars(r)["tag"])
+	}
+	if err != nil {
+		log.Printf("Error while try to remove a tag from a book: %v", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func ListBooksByTag(w http.ResponseWriter, r *http.Request) {
+	var books []model.Book
+
+	interactor, err := tagInteractorFor(r)
+	if err == nil {
+		books, err = interactor.ListBooksByTag(mux.Vars(r)["tag"])
+	}
+	if err != nil {
+		log.Printf("Error while try to find the books by tag: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	booksResult := make([]BookRequestBody, len(books))
+	for i, book := range books {
+		booksResult[i] = toBookResult(book)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(booksResult)
+}
@@ -0,0 +1,134 @@
+package breaker
+
+import (
+	"errors"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type state string
+
+const (
+	closed   state = "closed"
+	open     state = "open"
+	halfOpen state = "half-open"
+)
+
+var (
+	// metrics publishes the state and the failures of every breaker on
+	// /debug/vars
+	metrics = expvar.NewMap("breakers")
+)
+
+// Breaker stops calling a storage after a number of consecutive failures,
+// once the timeout has passed it lets one call through to probe if the
+// storage is back
+type Breaker struct {
+	mu        *sync.Mutex
+	name      string
+	threshold int
+	timeout   time.Duration
+	state     state
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	// generation changes with every state change, the calls started on an
+	// older state don't count once they finish
+	generation uint64
+	now        func() time.Time
+}
+
+func New(name string, threshold int, timeout time.Duration) *Breaker {
+	b := &Breaker{
+		mu:        &sync.Mutex{},
+		name:      name,
+		threshold: threshold,
+		timeout:   timeout,
+		state:     closed,
+		now:       time.Now,
+	}
+	metrics.Set(name, expvar.Func(b.metrics))
+	return b
+}
+
+// Call runs fn unless the breaker is open, in that case it returns an
+// unavailable error without calling it. Domain errors like not found don't
+// count as failures
+func (b *Breaker) Call(fn func() error) error {
+	generation, err := b.before()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	b.after(generation, err)
+	return err
+}
+
+// before returns the generation the call starts on, when the breaker goes
+// half open the probe is the only call of the new generation
+func (b *Breaker) before() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		elapsed := b.now().Sub(b.openedAt)
+		if elapsed < b.timeout {
+			return 0, domainerr.Unavailable(b.timeout-elapsed, "%s is not available", b.name)
+		}
+		b.setState(halfOpen)
+		b.probing = true
+	case halfOpen:
+		if b.probing {
+			return 0, domainerr.Unavailable(b.timeout, "%s is not available", b.name)
+		}
+		b.probing = true
+	}
+	return b.generation, nil
+}
+
+func (b *Breaker) after(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A slow call started before the breaker opened, or before the probe,
+	// says nothing about the storage now, only the probe decides
+	if generation != b.generation {
+		return
+	}
+	b.probing = false
+	var domainErr *domainerr.Error
+	if err == nil || errors.As(err, &domainErr) {
+		b.failures = 0
+		b.setState(closed)
+		return
+	}
+
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setState(open)
+	}
+}
+
+func (b *Breaker) setState(s state) {
+	if b.state != s {
+		log.Printf("Circuit breaker for %s changed from %s to %s", b.name, b.state, s)
+		b.state = s
+		b.generation++
+	}
+}
+
+func (b *Breaker) metrics() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return map[string]interface{}{
+		"state":    b.state,
+		"failures": b.failures,
+	}
+}
@@ -0,0 +1,111 @@
+package breaker
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+var (
+	errConnection = errors.New("connection refused")
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := New("postgres", 2, time.Minute)
+	calls := 0
+	failing := func() error {
+		calls++
+		return errConnection
+	}
+
+	b.Call(failing)
+	b.Call(failing)
+	err := b.Call(failing)
+
+	if calls != 2 {
+		t.Errorf("Should stop calling after 2 failures but got %d calls", calls)
+	}
+	if !errors.Is(err, domainerr.ErrUnavailable) {
+		t.Errorf("Should be an unavailable error but got %v", err)
+	}
+}
+
+func TestBreakerIgnoresDomainErrors(t *testing.T) {
+	b := New("postgres", 1, time.Minute)
+	b.Call(func() error {
+		return domainerr.NotFound("not found")
+	})
+
+	calls := 0
+	b.Call(func() error {
+		calls++
+		return nil
+	})
+	if calls != 1 {
+		t.Error("Domain errors shouldn't open the breaker")
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	b := New("postgres", 1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Call(func() error { return errConnection })
+	now = now.Add(time.Minute)
+
+	// The probe fails, so the breaker opens again
+	b.Call(func() error { return errConnection })
+	if err := b.Call(func() error { return nil }); !errors.Is(err, domainerr.ErrUnavailable) {
+		t.Errorf("Should be open again after a failed probe but got %v", err)
+	}
+
+	// The probe works, so the breaker closes
+	now = now.Add(time.Minute)
+	if err := b.Call(func() error { return nil }); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if b.state != closed {
+		t.Errorf("Should be closed after a successful probe but got %s", b.state)
+	}
+}
+
+func TestBreakerIgnoresStaleCalls(t *testing.T) {
+	now := time.Now()
+	b := New("postgres", 1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	// A slow call starts while the breaker is closed
+	slow, _ := b.before()
+	b.Call(func() error { return errConnection })
+	now = now.Add(time.Minute)
+	probe, err := b.before()
+	if err != nil {
+		t.Fatalf("The probe shouldn't be rejected but got %v", err)
+	}
+
+	b.after(slow, nil)
+	if b.state != halfOpen {
+		t.Errorf("A stale call shouldn't close the breaker but got %s", b.state)
+	}
+	b.after(probe, errConnection)
+	if b.state != open {
+		t.Errorf("The failed probe should open the breaker but got %s", b.state)
+	}
+}
+
+func TestBreakerMetrics(t *testing.T) {
+	b := New("metrics", 2, time.Minute)
+	b.Call(func() error { return errConnection })
+
+	var published map[string]interface{}
+	if err := json.Unmarshal([]byte(metrics.Get("metrics").String()), &published); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if published["state"] != "closed" || published["failures"] != float64(1) {
+		t.Errorf("Should publish the state and the failures but got %v", published)
+	}
+}
@@ -0,0 +1,321 @@
+package breaker
+
+import (
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+)
+
+type storage struct {
+	users      userRepository
+	books      bookRepository
+	tags       tagRepository
+	suppliers  supplierRepository
+	stocktakes stocktakeRepository
+}
+
+// NewStorage wraps all the repositories of the storage with the same
+// breaker, they all share the same database so they fail together
+func NewStorage(s repository.Storage, breaker *Breaker) *storage {
+	return &storage{
+		users:      userRepository{repo: s.Users(), breaker: breaker},
+		books:      bookRepository{repo: s.Books(), breaker: breaker},
+		tags:       tagRepository{repo: s.Tags(), breaker: breaker},
+		suppliers:  supplierRepository{repo: s.Suppliers(), breaker: breaker},
+		stocktakes: stocktakeRepository{repo: s.Stocktakes(), breaker: breaker},
+	}
+}
+
+func (s *storage) Users() repository.UserRepository {
+	return s.users
+}
+
+func (s *storage) Books() repository.BookRepository {
+	return s.books
+}
+
+func (s *storage) Tags() repository.TagRepository {
+	return s.tags
+}
+
+func (s *storage) Suppliers() repository.SupplierRepository {
+	return s.suppliers
+}
+
+func (s *storage) Stocktakes() repository.StocktakeRepository {
+	return s.stocktakes
+}
+
+type userRepository struct {
+	repo    repository.UserRepository
+	breaker *Breaker
+}
+
+func (r userRepository) FindAll() (users []*model.User, err error) {
+	err = r.breaker.Call(func() error {
+		users, err = r.repo.FindAll()
+		return err
+	})
+	return users, err
+}
+
+func (r userRepository) Count() (count int, err error) {
+	err = r.breaker.Call(func() error {
+		count, err = r.repo.Count()
+		return err
+	})
+	return count, err
+}
+
+func (r userRepository) FindByEmail(email string) (user *model.User, err error) {
+	err = r.breaker.Call(func() error {
+		user, err = r.repo.FindByEmail(email)
+		return err
+	})
+	return user, err
+}
+
+func (r userRepository) FindByID(id string) (user *model.User, err error) {
+	err = r.breaker.Call(func() error {
+		user, err = r.repo.FindByID(id)
+		return err
+	})
+	return user, err
+}
+
+func (r userRepository) Save(user *model.User) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Save(user)
+	})
+}
+
+func (r userRepository) Delete(user *model.User) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Delete(user)
+	})
+}
+
+func (r userRepository) FindDeletedByID(id string) (user *model.User, err error) {
+	err = r.breaker.Call(func() error {
+		user, err = r.repo.FindDeletedByID(id)
+		return err
+	})
+	return user, err
+}
+
+func (r userRepository) Restore(user *model.User) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Restore(user)
+	})
+}
+
+type bookRepository struct {
+	repo    repository.BookRepository
+	breaker *Breaker
+}
+
+func (r bookRepository) FindAll() (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindAll()
+		return err
+	})
+	return books, err
+}
+
+func (r bookRepository) Count() (count int, err error) {
+	err = r.breaker.Call(func() error {
+		count, err = r.repo.Count()
+		return err
+	})
+	return count, err
+}
+
+func (r bookRepository) FindByID(id string) (book model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		book, err = r.repo.FindByID(id)
+		return err
+	})
+	return book, err
+}
+
+func (r bookRepository) FindByIDs(ids []string) (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindByIDs(ids)
+		return err
+	})
+	return books, err
+}
+
+func (r bookRepository) FindByISBN(ISBN string) (book model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		book, err = r.repo.FindByISBN(ISBN)
+		return err
+	})
+	return book, err
+}
+
+func (r bookRepository) FindByLanguage(language string) (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindByLanguage(language)
+		return err
+	})
+	return books, err
+}
+
+func (r bookRepository) CountByLanguage() (counts map[string]int, err error) {
+	err = r.breaker.Call(func() error {
+		counts, err = r.repo.CountByLanguage()
+		return err
+	})
+	return counts, err
+}
+
+func (r bookRepository) FindBySupplier(supplierID string) (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindBySupplier(supplierID)
+		return err
+	})
+	return books, err
+}
+
+func (r bookRepository) FindAddedSince(since time.Time) (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindAddedSince(since)
+		return err
+	})
+	return books, err
+}
+
+func (r bookRepository) FindHistory(id string) (history []*model.BookChange, err error) {
+	err = r.breaker.Call(func() error {
+		history, err = r.repo.FindHistory(id)
+		return err
+	})
+	return history, err
+}
+
+func (r bookRepository) Save(book model.Book) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Save(book)
+	})
+}
+
+func (r bookRepository) Delete(id string) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Delete(id)
+	})
+}
+
+func (r bookRepository) DeleteMany(ids []string) error {
+	return r.breaker.Call(func() error {
+		return r.repo.DeleteMany(ids)
+	})
+}
+
+type tagRepository struct {
+	repo    repository.TagRepository
+	breaker *Breaker
+}
+
+func (r tagRepository) AddTag(bookID, tag string) error {
+	return r.breaker.Call(func() error {
+		return r.repo.AddTag(bookID, tag)
+	})
+}
+
+func (r tagRepository) RemoveTag(bookID, tag string) error {
+	return r.breaker.Call(func() error {
+		return r.repo.RemoveTag(bookID, tag)
+	})
+}
+
+func (r tagRepository) FindBooksByTag(tag string) (books []model.Book, err error) {
+	err = r.breaker.Call(func() error {
+		books, err = r.repo.FindBooksByTag(tag)
+		return err
+	})
+	return books, err
+}
+
+type supplierRepository struct {
+	repo    repository.SupplierRepository
+	breaker *Breaker
+}
+
+func (r supplierRepository) FindAll() (suppliers []*model.Supplier, err error) {
+	err = r.breaker.Call(func() error {
+		suppliers, err = r.repo.FindAll()
+		return err
+	})
+	return suppliers, err
+}
+
+func (r supplierRepository) FindByID(id string) (supplier *model.Supplier, err error) {
+	err = r.breaker.Call(func() error {
+		supplier, err = r.repo.FindByID(id)
+		return err
+	})
+	return supplier, err
+}
+
+func (r supplierRepository) Save(supplier *model.Supplier) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Save(supplier)
+	})
+}
+
+func (r supplierRepository) Delete(id string) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Delete(id)
+	})
+}
+
+type stocktakeRepository struct {
+	repo    repository.StocktakeRepository
+	breaker *Breaker
+}
+
+func (r stocktakeRepository) FindAll() (stocktakes []*model.Stocktake, err error) {
+	err = r.breaker.Call(func() error {
+		stocktakes, err = r.repo.FindAll()
+		return err
+	})
+	return stocktakes, err
+}
+
+func (r stocktakeRepository) FindByID(id string) (stocktake *model.Stocktake, err error) {
+	err = r.breaker.Call(func() error {
+		stocktake, err = r.repo.FindByID(id)
+		return err
+	})
+	return stocktake, err
+}
+
+func (r stocktakeRepository) Create(stocktake *model.Stocktake) (id string, err error) {
+	err = r.breaker.Call(func() error {
+		id, err = r.repo.Create(stocktake)
+		return err
+	})
+	return id, err
+}
+
+func (r stocktakeRepository) Close(id string, closedAt time.Time) error {
+	return r.breaker.Call(func() error {
+		return r.repo.Close(id, closedAt)
+	})
+}
+
+func (r stocktakeRepository) AddScans(id string, scans []*model.StocktakeScan) error {
+	return r.breaker.Call(func() error {
+		return r.repo.AddScans(id, scans)
+	})
+}
+
+func (r stocktakeRepository) FindScans(id string) (scans []*model.StocktakeScan, err error) {
+	err = r.breaker.Call(func() error {
+		scans, err = r.repo.FindScans(id)
+		return err
+	})
+	return scans, err
+}
@@ -0,0 +1,64 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+)
+
+type mockDatabase struct {
+	db *gorm.DB
+}
+
+func (m mockDatabase) DB() *gorm.DB {
+	return m.db
+}
+
+func (m mockDatabase) ReadDB() *gorm.DB {
+	return m.db
+}
+
+// The ids of the request path are sent by the clients, a wrong one must not
+// reach postgres, it would fail the query and open the breaker for everyone
+func TestStorageNotValidIDsDontOpenTheBreaker(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error creating the mock but got %v", err)
+	}
+	db, err := gorm.Open("postgres", sqlDB)
+	if err != nil {
+		t.Fatalf("Shouldn't be an error opening gorm but got %v", err)
+	}
+	defer db.Close()
+
+	b := New("postgres", 1, time.Minute)
+	storage := NewStorage(relational.NewStorage(mockDatabase{db: db}, func(error) bool { return false }), b)
+	for _, id := range []string{"notANumber", "0", "-1"} {
+		if book, err := storage.Books().FindByID(id); book != nil || err != nil {
+			t.Errorf("Should return a book and an error nil for %s but got book %v err %v", id, book, err)
+		}
+		if user, err := storage.Users().FindByID(id); user != nil || err != nil {
+			t.Errorf("Should return a user and an error nil for %s but got user %v err %v", id, user, err)
+		}
+		if supplier, err := storage.Suppliers().FindByID(id); supplier != nil || err != nil {
+			t.Errorf("Should return a supplier and an error nil for %s but got supplier %v err %v", id, supplier, err)
+		}
+		if stocktake, err := storage.Stocktakes().FindByID(id); stocktake != nil || err != nil {
+			t.Errorf("Should return a stocktake and an error nil for %s but got stocktake %v err %v", id, stocktake, err)
+		}
+		if err := storage.Books().Delete(id); err != nil {
+			t.Errorf("Shouldn't be an error removing %s but got %v", id, err)
+		}
+	}
+
+	if b.state != closed || b.failures != 0 {
+		t.Errorf("The breaker should stay closed but got %s with %d failures", b.state, b.failures)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,258 @@
+package counting
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+)
+
+// Counter counts the calls made to a storage, on the database storages a call
+// runs one or more queries so it helps to spot the N+1 patterns
+type Counter struct {
+	calls int64
+}
+
+func (c *Counter) add() {
+	atomic.AddInt64(&c.calls, 1)
+}
+
+func (c *Counter) Count() int {
+	return int(atomic.LoadInt64(&c.calls))
+}
+
+type storage struct {
+	users      userRepository
+	books      bookRepository
+	tags       tagRepository
+	suppliers  supplierRepository
+	stocktakes stocktakeRepository
+}
+
+// NewStorage wraps all the repositories of the storage, every call on any of
+// them adds one to the counter
+func NewStorage(s repository.Storage, counter *Counter) *storage {
+	return &storage{
+		users:      userRepository{repo: s.Users(), counter: counter},
+		books:      bookRepository{repo: s.Books(), counter: counter},
+		tags:       tagRepository{repo: s.Tags(), counter: counter},
+		suppliers:  supplierRepository{repo: s.Suppliers(), counter: counter},
+		stocktakes: stocktakeRepository{repo: s.Stocktakes(), counter: counter},
+	}
+}
+
+func (s *storage) Users() repository.UserRepository {
+	return s.users
+}
+
+func (s *storage) Books() repository.BookRepository {
+	return s.books
+}
+
+func (s *storage) Tags() repository.TagRepository {
+	return s.tags
+}
+
+func (s *storage) Suppliers() repository.SupplierRepository {
+	return s.suppliers
+}
+
+func (s *storage) Stocktakes() repository.StocktakeRepository {
+	return s.stocktakes
+}
+
+type userRepository struct {
+	repo    repository.UserRepository
+	counter *Counter
+}
+
+func (r userRepository) FindAll() ([]*model.User, error) {
+	r.counter.add()
+	return r.repo.FindAll()
+}
+
+func (r userRepository) Count() (int, error) {
+	r.counter.add()
+	return r.repo.Count()
+}
+
+func (r userRepository) FindByEmail(email string) (*model.User, error) {
+	r.counter.add()
+	return r.repo.FindByEmail(email)
+}
+
+func (r userRepository) FindByID(id string) (*model.User, error) {
+	r.counter.add()
+	return r.repo.FindByID(id)
+}
+
+func (r userRepository) Save(user *model.User) error {
+	r.counter.add()
+	return r.repo.Save(user)
+}
+
+func (r userRepository) Delete(user *model.User) error {
+	r.counter.add()
+	return r.repo.Delete(user)
+}
+
+func (r userRepository) FindDeletedByID(id string) (*model.User, error) {
+	r.counter.add()
+	return r.repo.FindDeletedByID(id)
+}
+
+func (r userRepository) Restore(user *model.User) error {
+	r.counter.add()
+	return r.repo.Restore(user)
+}
+
+type bookRepository struct {
+	repo    repository.BookRepository
+	counter *Counter
+}
+
+func (r bookRepository) FindAll() ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindAll()
+}
+
+func (r bookRepository) Count() (int, error) {
+	r.counter.add()
+	return r.repo.Count()
+}
+
+func (r bookRepository) FindByID(id string) (model.Book, error) {
+	r.counter.add()
+	return r.repo.FindByID(id)
+}
+
+func (r bookRepository) FindByIDs(ids []string) ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindByIDs(ids)
+}
+
+func (r bookRepository) FindByISBN(ISBN string) (model.Book, error) {
+	r.counter.add()
+	return r.repo.FindByISBN(ISBN)
+}
+
+func (r bookRepository) FindByLanguage(language string) ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindByLanguage(language)
+}
+
+func (r bookRepository) CountByLanguage() (map[string]int, error) {
+	r.counter.add()
+	return r.repo.CountByLanguage()
+}
+
+func (r bookRepository) FindBySupplier(supplierID string) ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindBySupplier(supplierID)
+}
+
+func (r bookRepository) FindAddedSince(since time.Time) ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindAddedSince(since)
+}
+
+func (r bookRepository) FindHistory(id string) ([]*model.BookChange, error) {
+	r.counter.add()
+	return r.repo.FindHistory(id)
+}
+
+func (r bookRepository) Save(book model.Book) error {
+	r.counter.add()
+	return r.repo.Save(book)
+}
+
+func (r bookRepository) Delete(id string) error {
+	r.counter.add()
+	return r.repo.Delete(id)
+}
+
+func (r bookRepository) DeleteMany(ids []string) error {
+	r.counter.add()
+	return r.repo.DeleteMany(ids)
+}
+
+type tagRepository struct {
+	repo    repository.TagRepository
+	counter *Counter
+}
+
+func (r tagRepository) AddTag(bookID, tag string) error {
+	r.counter.add()
+	return r.repo.AddTag(bookID, tag)
+}
+
+func (r tagRepository) RemoveTag(bookID, tag string) error {
+	r.counter.add()
+	return r.repo.RemoveTag(bookID, tag)
+}
+
+func (r tagRepository) FindBooksByTag(tag string) ([]model.Book, error) {
+	r.counter.add()
+	return r.repo.FindBooksByTag(tag)
+}
+
+type supplierRepository struct {
+	repo    repository.SupplierRepository
+	counter *Counter
+}
+
+func (r supplierRepository) FindAll() ([]*model.Supplier, error) {
+	r.counter.add()
+	return r.repo.FindAll()
+}
+
+func (r supplierRepository) FindByID(id string) (*model.Supplier, error) {
+	r.counter.add()
+	return r.repo.FindByID(id)
+}
+
+func (r supplierRepository) Save(supplier *model.Supplier) error {
+	r.counter.add()
+	return r.repo.Save(supplier)
+}
+
+func (r supplierRepository) Delete(id string) error {
+	r.counter.add()
+	return r.repo.Delete(id)
+}
+
+type stocktakeRepository struct {
+	repo    repository.StocktakeRepository
+	counter *Counter
+}
+
+func (r stocktakeRepository) FindAll() ([]*model.Stocktake, error) {
+	r.counter.add()
+	return r.repo.FindAll()
+}
+
+func (r stocktakeRepository) FindByID(id string) (*model.Stocktake, error) {
+	r.counter.add()
+	return r.repo.FindByID(id)
+}
+
+func (r stocktakeRepository) Create(stocktake *model.Stocktake) (string, error) {
+	r.counter.add()
+	return r.repo.Create(stocktake)
+}
+
+func (r stocktakeRepository) Close(id string, closedAt time.Time) error {
+	r.counter.add()
+	return r.repo.Close(id, closedAt)
+}
+
+func (r stocktakeRepository) AddScans(id string, scans []*model.StocktakeScan) error {
+	r.counter.add()
+	return r.repo.AddScans(id, scans)
+}
+
+func (r stocktakeRepository) FindScans(id string) ([]*model.StocktakeScan, error) {
+	r.counter.add()
+	return r.repo.FindScans(id)
+}
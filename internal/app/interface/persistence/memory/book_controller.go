@@ -1,18 +1,29 @@
 package memory
 
 import (
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type Book struct {
-	ID    string
-	Title string
-	ISBN  string
-	Price float64
-	User  *model.User
+	ID               string
+	Title            string
+	ISBN             string
+	Price            float64
+	Language         string
+	OriginalLanguage string
+	Location         string
+	SupplierID       string
+	User             *model.User
+
+	createdAt time.Time
+	updatedAt time.Time
 }
 
 func (b Book) GetID() string {
@@ -31,19 +42,45 @@ func (b Book) GetPrice() float64 {
 	return b.Price
 }
 
+func (b Book) GetLanguage() string {
+	return b.Language
+}
+
+func (b Book) GetOriginalLanguage() string {
+	return b.OriginalLanguage
+}
+
+func (b Book) GetLocation() string {
+	return b.Location
+}
+
 func (b Book) GetUser() *model.User {
 	return b.User
 }
 
+func (b Book) GetSupplierID() string {
+	return b.SupplierID
+}
+
+func (b Book) GetCreatedAt() time.Time {
+	return b.createdAt
+}
+
+func (b Book) GetUpdatedAt() time.Time {
+	return b.updatedAt
+}
+
 type bookController struct {
-	mu    *sync.Mutex
-	books map[string]Book
+	mu      *sync.Mutex
+	books   map[string]Book
+	history map[string][]*model.BookChange
 }
 
 func NewBookController() *bookController {
 	return &bookController{
-		mu:    &sync.Mutex{},
-		books: map[string]Book{},
+		mu:      &sync.Mutex{},
+		books:   map[string]Book{},
+		history: map[string][]*model.BookChange{},
 	}
 }
 
@@ -51,13 +88,51 @@ func (r bookController) FindAll() ([]model.Book, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	books := make([]model.Book, len(r.books))
-	i := 0
+	stored := make([]Book, 0, len(r.books))
 	for _, book := range r.books {
+		stored = append(stored, book)
+	}
+	return newestFirst(stored), nil
+}
+
+func (r bookController) FindByIDs(ids []string) ([]model.Book, error) {
+	return r.findByIDs(ids), nil
+}
+
+// findByIDs skips the ids of the books that don't exist
+func (r bookController) findByIDs(ids []string) []model.Book {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]Book, 0, len(ids))
+	for _, id := range ids {
+		if book, ok := r.books[id]; ok {
+			stored = append(stored, book)
+		}
+	}
+	return newestFirst(stored)
+}
+
+// newestFirst sorts the books in the same order as the database storages
+func newestFirst(stored []Book) []model.Book {
+	sort.Slice(stored, func(i, j int) bool {
+		if !stored[i].createdAt.Equal(stored[j].createdAt) {
+			return stored[i].createdAt.After(stored[j].createdAt)
+		}
+		return stored[i].ID > stored[j].ID
+	})
+	books := make([]model.Book, len(stored))
+	for i, book := range stored {
 		books[i] = book
-		i++
 	}
-	return books, nil
+	return books
+}
+
+func (r bookController) Count() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.books), nil
 }
 
 func (r bookController) FindByID(id string) (model.Book, error) {
@@ -83,35 +158,122 @@ func (r bookController) FindByISBN(ISBN string) (model.Book, error) {
 	return nil, nil
 }
 
+func (r bookController) FindByLanguage(language string) ([]model.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]Book, 0)
+	for _, book := range r.books {
+		if normalizeLanguage(book.GetLanguage()) == language {
+			stored = append(stored, book)
+		}
+	}
+	return newestFirst(stored), nil
+}
+
+func (r bookController) CountByLanguage() (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, book := range r.books {
+		if language := normalizeLanguage(book.GetLanguage()); language != "" {
+			counts[language]++
+		}
+	}
+	return counts, nil
+}
+
+func (r bookController) FindBySupplier(supplierID string) ([]model.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]Book, 0)
+	for _, book := range r.books {
+		if book.SupplierID == supplierID {
+			stored = append(stored, book)
+		}
+	}
+	return newestFirst(stored), nil
+}
+
+func (r bookController) FindAddedSince(since time.Time) ([]model.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]Book, 0)
+	for _, book := range r.books {
+		if !book.createdAt.Before(since) {
+			stored = append(stored, book)
+		}
+	}
+	return newestFirst(stored), nil
+}
+
+func (r bookController) FindHistory(id string) ([]*model.BookChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*model.BookChange{}, r.history[id]...), nil
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+// Save fails with not found when the book has an id that isn't stored, like
+// the database storages
 func (r bookController) Save(book model.Book) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if book.GetID() != "" {
-		r.books[book.GetID()] = Book{
-			ID:    book.GetID(),
-			Title: book.GetTitle(),
-			ISBN:  book.GetISBN(),
-			Price: book.GetPrice(),
-			User:  book.GetUser(),
+		stored, ok := r.books[book.GetID()]
+		if !ok {
+			return domainerr.NotFound("Book with id: %s not found", book.GetID())
+		}
+		if fields := model.DiffBooks(stored, book); len(fields) > 0 {
+			r.history[book.GetID()] = append(r.history[book.GetID()], model.NewBookChange(book.GetID(), fields, time.Now()))
 		}
+		r.books[book.GetID()] = newBook(book.GetID(), book, stored.createdAt)
 	} else {
 		uid, err := uuid.NewRandom()
 		if err != nil {
 			return err
 		}
-		r.books[uid.String()] = Book{
-			ID:    uid.String(),
-			Title: book.GetTitle(),
-			ISBN:  book.GetISBN(),
-			Price: book.GetPrice(),
-			User:  book.GetUser(),
-		}
+		r.books[uid.String()] = newBook(uid.String(), book, time.Now())
 	}
 
 	return nil
 }
 
+// Load stores the books with the ids they already have, like restoring a
+// backup, Save only gives ids to the new books
+func (r bookController) Load(books ...model.Book) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, book := range books {
+		r.books[book.GetID()] = newBook(book.GetID(), book, time.Now())
+	}
+}
+
+func newBook(id string, book model.Book, createdAt time.Time) Book {
+	return Book{
+		ID:               id,
+		Title:            book.GetTitle(),
+		ISBN:             book.GetISBN(),
+		Price:            book.GetPrice(),
+		Language:         book.GetLanguage(),
+		OriginalLanguage: book.GetOriginalLanguage(),
+		Location:         book.GetLocation(),
+		SupplierID:       book.GetSupplierID(),
+		User:             book.GetUser(),
+		createdAt:        createdAt,
+		updatedAt:        time.Now(),
+	}
+}
+
 func (r bookController) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -120,3 +282,24 @@ func (r bookController) Delete(id string) error {
 
 	return nil
 }
+
+// DeleteMany checks all the books before removing any of them, like the
+// transaction of the database storages
+func (r bookController) DeleteMany(ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		book, ok := r.books[id]
+		if !ok {
+			return domainerr.Conflict("Book with id: %s no longer exists", id)
+		} else if book.User != nil {
+			return domainerr.Conflict("Book with id: %s is rented", id)
+		}
+	}
+	for _, id := range ids {
+		delete(r.books, id)
+	}
+
+	return nil
+}
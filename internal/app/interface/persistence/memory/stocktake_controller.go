@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type Stocktake struct {
+	ID        string
+	Location  string
+	StartedAt time.Time
+	ClosedAt  time.Time
+	Scans     []*model.StocktakeScan
+}
+
+type stocktakeController struct {
+	mu         *sync.Mutex
+	stocktakes map[string]*Stocktake
+}
+
+func NewStocktakeController() *stocktakeController {
+	return &stocktakeController{
+		mu:         &sync.Mutex{},
+		stocktakes: map[string]*Stocktake{},
+	}
+}
+
+func (r stocktakeController) FindAll() ([]*model.Stocktake, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktakes := make([]*model.Stocktake, 0, len(r.stocktakes))
+	for _, stocktake := range r.stocktakes {
+		stocktakes = append(stocktakes, stocktake.toModel())
+	}
+	// Same order as the database storages, the newest first
+	sort.Slice(stocktakes, func(i, j int) bool {
+		if !stocktakes[i].GetStartedAt().Equal(stocktakes[j].GetStartedAt()) {
+			return stocktakes[i].GetStartedAt().After(stocktakes[j].GetStartedAt())
+		}
+		return stocktakes[i].GetID() > stocktakes[j].GetID()
+	})
+	return stocktakes, nil
+}
+
+func (r stocktakeController) FindByID(id string) (*model.Stocktake, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktakes[id]
+	if !ok {
+		return nil, nil
+	}
+	return stocktake.toModel(), nil
+}
+
+func (r stocktakeController) Create(stocktake *model.Stocktake) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uid, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+	r.stocktakes[uid.String()] = &Stocktake{
+		ID:        uid.String(),
+		Location:  stocktake.GetLocation(),
+		StartedAt: stocktake.GetStartedAt(),
+	}
+	return uid.String(), nil
+}
+
+func (r stocktakeController) Close(id string, closedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktakes[id]
+	if !ok {
+		return domainerr.NotFound("Stocktake with id: %s not found", id)
+	}
+	stocktake.ClosedAt = closedAt
+	return nil
+}
+
+func (r stocktakeController) AddScans(id string, scans []*model.StocktakeScan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktakes[id]
+	if !ok {
+		return domainerr.NotFound("Stocktake with id: %s not found", id)
+	}
+	stocktake.Scans = append(stocktake.Scans, scans...)
+	return nil
+}
+
+func (r stocktakeController) FindScans(id string) ([]*model.StocktakeScan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stocktake, ok := r.stocktakes[id]
+	if !ok {
+		return []*model.StocktakeScan{}, nil
+	}
+	return append([]*model.StocktakeScan{}, stocktake.Scans...), nil
+}
+
+func (s *Stocktake) toModel() *model.Stocktake {
+	return model.NewStocktake(s.ID, s.Location, s.StartedAt, s.ClosedAt)
+}
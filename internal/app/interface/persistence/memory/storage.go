@@ -0,0 +1,42 @@
+package memory
+
+import "github.com/ramonmacias/librarium/internal/app/domain/repository"
+
+type storage struct {
+	users      *userController
+	books      *bookController
+	tags       *tagController
+	suppliers  *supplierController
+	stocktakes *stocktakeController
+}
+
+func NewStorage() *storage {
+	books := NewBookController()
+	return &storage{
+		users:      NewUserController(),
+		books:      books,
+		tags:       NewTagController(books),
+		suppliers:  NewSupplierController(),
+		stocktakes: NewStocktakeController(),
+	}
+}
+
+func (s *storage) Users() repository.UserRepository {
+	return s.users
+}
+
+func (s *storage) Books() repository.BookRepository {
+	return s.books
+}
+
+func (s *storage) Tags() repository.TagRepository {
+	return s.tags
+}
+
+func (s *storage) Suppliers() repository.SupplierRepository {
+	return s.suppliers
+}
+
+func (s *storage) Stocktakes() repository.StocktakeRepository {
+	return s.stocktakes
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type Supplier struct {
+	ID         string
+	Name       string
+	Contact    string
+	Categories []string
+}
+
+type supplierController struct {
+	mu        *sync.Mutex
+	suppliers map[string]*Supplier
+}
+
+func NewSupplierController() *supplierController {
+	return &supplierController{
+		mu:        &sync.Mutex{},
+		suppliers: map[string]*Supplier{},
+	}
+}
+
+func (r supplierController) FindAll() ([]*model.Supplier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suppliers := make([]*model.Supplier, 0, len(r.suppliers))
+	for _, supplier := range r.suppliers {
+		suppliers = append(suppliers, supplier.toModel())
+	}
+	// Same order as the database storages, by name and then by id
+	sort.Slice(suppliers, func(i, j int) bool {
+		if suppliers[i].GetName() != suppliers[j].GetName() {
+			return suppliers[i].GetName() < suppliers[j].GetName()
+		}
+		return suppliers[i].GetID() < suppliers[j].GetID()
+	})
+	return suppliers, nil
+}
+
+func (r supplierController) FindByID(id string) (*model.Supplier, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	supplier, ok := r.suppliers[id]
+	if !ok {
+		return nil, nil
+	}
+	return supplier.toModel(), nil
+}
+
+func (r supplierController) Save(supplier *model.Supplier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := supplier.GetID()
+	if id == "" {
+		uid, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		id = uid.String()
+	} else if _, ok := r.suppliers[id]; !ok {
+		return domainerr.NotFound("Supplier with id: %s not found", id)
+	}
+
+	r.suppliers[id] = &Supplier{
+		ID:         id,
+		Name:       supplier.GetName(),
+		Contact:    supplier.GetContact(),
+		Categories: append([]string(nil), supplier.GetCategories()...),
+	}
+	return nil
+}
+
+func (r supplierController) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.suppliers, id)
+	return nil
+}
+
+func (s *Supplier) toModel() *model.Supplier {
+	return model.NewSupplier(s.ID, s.Name, s.Contact, append([]string(nil), s.Categories...))
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+type tagController struct {
+	mu    *sync.Mutex
+	tags  map[string]map[string]bool
+	books *bookController
+}
+
+// NewTagController needs the books to list the tagged ones, like the join
+// done by the databases
+func NewTagController(books *bookController) *tagController {
+	return &tagController{
+		mu:    &sync.Mutex{},
+		tags:  map[string]map[string]bool{},
+		books: books,
+	}
+}
+
+func (r tagController) AddTag(bookID, tag string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tags[tag]; !ok {
+		r.tags[tag] = map[string]bool{}
+	}
+	r.tags[tag][bookID] = true
+	return nil
+}
+
+func (r tagController) RemoveTag(bookID, tag string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tags[tag], bookID)
+	return nil
+}
+
+func (r tagController) FindBooksByTag(tag string) ([]model.Book, error) {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.tags[tag]))
+	for id := range r.tags[tag] {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+
+	return r.books.findByIDs(ids), nil
+}
@@ -1,7 +1,8 @@
 package memory
 
 import (
-	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
@@ -10,6 +11,8 @@ import (
 type userController struct {
 	mu    *sync.Mutex
 	users map[string]*User
+	// deleted keeps the removed users so they can be restored
+	deleted map[string]*User
 }
 
 type User struct {
@@ -21,8 +24,9 @@ type User struct {
 
 func NewUserController() *userController {
 	return &userController{
-		mu:    &sync.Mutex{},
-		users: map[string]*User{},
+		mu:      &sync.Mutex{},
+		users:   map[string]*User{},
+		deleted: map[string]*User{},
 	}
 }
 
@@ -36,15 +40,32 @@ func (r userController) FindAll() ([]*model.User, error) {
 		users[i] = model.NewUser(user.ID, user.Email, user.Name, user.LastName)
 		i++
 	}
+	// Same order as the database storages, by last name and then by name
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].GetLastName() != users[j].GetLastName() {
+			return users[i].GetLastName() < users[j].GetLastName()
+		}
+		if users[i].GetName() != users[j].GetName() {
+			return users[i].GetName() < users[j].GetName()
+		}
+		return users[i].GetID() < users[j].GetID()
+	})
 	return users, nil
 }
 
+func (r userController) Count() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users), nil
+}
+
 func (r userController) FindByEmail(email string) (*model.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for _, user := range r.users {
-		if user.Email == email {
+		if strings.EqualFold(user.Email, email) {
 			return model.NewUser(user.ID, user.Email, user.Name, user.LastName), nil
 		}
 	}
@@ -57,7 +78,7 @@ func (r userController) FindByID(id string) (*model.User, error) {
 
 	user, ok := r.users[id]
 	if !ok {
-		return nil, fmt.Errorf("User with id: %s not found", id)
+		return nil, nil
 	}
 	return model.NewUser(user.ID, user.Email, user.Name, user.LastName), nil
 }
@@ -79,7 +100,33 @@ func (r userController) Delete(user *model.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.users, user.GetID())
+	if stored, ok := r.users[user.GetID()]; ok {
+		r.deleted[user.GetID()] = stored
+		delete(r.users, user.GetID())
+	}
+
+	return nil
+}
+
+func (r userController) FindDeletedByID(id string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.deleted[id]
+	if !ok {
+		return nil, nil
+	}
+	return model.NewUser(user.ID, user.Email, user.Name, user.LastName), nil
+}
+
+func (r userController) Restore(user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.deleted[user.GetID()]; ok {
+		r.users[user.GetID()] = stored
+		delete(r.deleted, user.GetID())
+	}
 
 	return nil
 }
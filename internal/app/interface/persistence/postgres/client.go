@@ -3,23 +3,38 @@ package postgres
 import (
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
 )
 
 type client struct {
-	host     string
-	port     string
-	user     string
-	dbname   string
-	password string
+	host               string
+	port               string
+	user               string
+	dbname             string
+	password           string
+	replicaDSN         string
+	slowQueryThreshold time.Duration
 }
 
 type Connection struct {
 	conn *gorm.DB
+	// replica is checked on the background, so the reads don't pay an extra
+	// round trip, and it's only used while it answers the checks
+	mu             sync.RWMutex
+	replica        *gorm.DB
+	replicaHealthy bool
+	replicaChecked bool
 }
 
+const (
+	replicaCheckInterval = 10 * time.Second
+)
+
 var (
 	connInstance *Connection
 )
@@ -34,19 +49,110 @@ func NewClient(host, port, user, dbname, password string) *client {
 	}
 }
 
+// WithReplica sets the DSN of a read-only replica, when it's empty all the
+// queries will go to the primary database
+func (c *client) WithReplica(dsn string) *client {
+	c.replicaDSN = dsn
+	return c
+}
+
+// WithSlowQueryThreshold logs every query that takes longer than the given
+// duration
+func (c *client) WithSlowQueryThreshold(threshold time.Duration) *client {
+	c.slowQueryThreshold = threshold
+	return c
+}
+
+func (c *client) dsn() string {
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", c.host, c.port, c.user, c.dbname, c.password)
+}
+
 func (c *client) Connect() *Connection {
 	if connInstance == nil {
-		db, err := gorm.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable", c.host, c.port, c.user, c.dbname, c.password))
+		db, err := gorm.Open("postgres", c.dsn())
 		if err != nil {
 			log.Panicf("Error trying to connect: %v", err)
 		}
+		registerSlowQueryLogger(db, c.slowQueryThreshold)
 		connInstance = &Connection{
 			conn: db,
 		}
+		if c.replicaDSN != "" {
+			connInstance.watchReplica(c.openReplica, replicaCheckInterval)
+		}
 	}
 	return connInstance
 }
 
+func (c *client) openReplica() (*gorm.DB, error) {
+	db, err := gorm.Open("postgres", c.replicaDSN)
+	if err != nil {
+		return nil, err
+	}
+	registerSlowQueryLogger(db, c.slowQueryThreshold)
+	return db, nil
+}
+
+// Migrate creates or updates the tables of all the models
+func (c *Connection) Migrate() error {
+	return relational.Migrate(c.conn)
+}
+
 func (c *Connection) DB() *gorm.DB {
 	return c.conn
 }
+
+// ReadDB returns the replica if it answered the last health check, otherwise
+// it falls back to the primary database
+func (c *Connection) ReadDB() *gorm.DB {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.replica != nil && c.replicaHealthy {
+		return c.replica
+	}
+	return c.conn
+}
+
+// watchReplica checks the replica right away and then on every interval, a
+// replica that wasn't available at the start is opened once it answers
+func (c *Connection) watchReplica(open func() (*gorm.DB, error), interval time.Duration) {
+	c.checkReplica(open)
+	go func() {
+		for range time.Tick(interval) {
+			c.checkReplica(open)
+		}
+	}()
+}
+
+func (c *Connection) checkReplica(open func() (*gorm.DB, error)) {
+	c.mu.RLock()
+	replica := c.replica
+	c.mu.RUnlock()
+	if replica == nil {
+		db, err := open()
+		if err != nil {
+			c.setReplicaHealth(err)
+			return
+		}
+		c.mu.Lock()
+		c.replica = db
+		c.mu.Unlock()
+		replica = db
+	}
+	c.setReplicaHealth(replica.DB().Ping())
+}
+
+// setReplicaHealth only logs the changes, the check runs too often to log
+// every result
+func (c *Connection) setReplicaHealth(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	healthy := err == nil
+	if healthy && !c.replicaHealthy {
+		log.Println("Replica available, reads will use the replica")
+	} else if !healthy && (c.replicaHealthy || !c.replicaChecked) {
+		log.Printf("Replica not available, reads will use the primary: %v", err)
+	}
+	c.replicaHealthy = healthy
+	c.replicaChecked = true
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+)
+
+// newMockConnection builds a connection backed by sqlmock, so the
+// controllers can be tested without a running database
+func newMockConnection(t *testing.T) (*Connection, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error creating the mock but got %v", err)
+	}
+	db, err := gorm.Open("postgres", sqlDB)
+	if err != nil {
+		t.Fatalf("Shouldn't be an error opening gorm but got %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return &Connection{conn: db}, mock
+}
+
+func TestReadDBFallsBackToPrimary(t *testing.T) {
+	conn, _ := newMockConnection(t)
+	if conn.ReadDB() != conn.DB() {
+		t.Error("Without a replica the reads should go to the primary")
+	}
+}
+
+func TestReadDBUsesTheHealthyReplica(t *testing.T) {
+	conn, _ := newMockConnection(t)
+	sqlDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("Shouldn't be an error creating the mock but got %v", err)
+	}
+	defer sqlDB.Close()
+	var replica *gorm.DB
+	opens := 0
+	open := func() (*gorm.DB, error) {
+		opens++
+		if opens == 1 {
+			return nil, errors.New("replica down")
+		}
+		mock.ExpectPing()
+		replica, err = gorm.Open("postgres", sqlDB)
+		return replica, err
+	}
+
+	conn.checkReplica(open)
+	if conn.ReadDB() != conn.DB() {
+		t.Error("A replica that can't be opened should fall back to the primary")
+	}
+
+	mock.ExpectPing()
+	conn.checkReplica(open)
+	if conn.ReadDB() != replica {
+		t.Error("The reads should go to the replica once it's available")
+	}
+
+	mock.ExpectPing().WillReturnError(errors.New("replica down"))
+	conn.checkReplica(open)
+	if conn.ReadDB() != conn.DB() {
+		t.Error("The reads should go to the primary when the replica stops answering")
+	}
+	if opens != 2 {
+		t.Errorf("The replica should only be opened again while it's missing but got %d opens", opens)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectExec(`SELECT pg_notify\(\$1, ''\)`).
+		WithArgs(StatsChannel).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := conn.Notify(StatsChannel); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// StatsChannel is where the changes that make the cached stats and feed
+	// stale are announced to all the instances sharing the database
+	StatsChannel = "librarium_stats"
+
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+)
+
+// Notify sends a notification to everyone listening on the channel, this
+// instance included
+func (c *Connection) Notify(channel string) error {
+	return c.conn.Exec("SELECT pg_notify(?, '')", channel).Error
+}
+
+// Listen calls onNotify every time a notification arrives on the channel. The
+// listener reconnects on its own, and onNotify is also called after that
+// since the notifications sent meanwhile are lost
+func (c *client) Listen(channel string, onNotify func()) error {
+	listener := pq.NewListener(c.dsn(), minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Printf("Listener on %s disconnected: %v", channel, err)
+		case pq.ListenerEventReconnected:
+			log.Printf("Listener on %s reconnected", channel)
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Printf("Listener on %s failed to reconnect: %v", channel, err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return err
+	}
+	go func() {
+		for range listener.Notify {
+			// A nil notification means the listener reconnected
+			onNotify()
+		}
+	}()
+	return nil
+}
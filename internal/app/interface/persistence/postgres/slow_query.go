@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	startedAtKey = "librarium:started_at"
+)
+
+var (
+	closureSuffix = regexp.MustCompile(`(\.func\d+)(\.\d+)*$`)
+)
+
+// registerSlowQueryLogger logs all the queries that take more than the given
+// threshold, a zero threshold disables it
+func registerSlowQueryLogger(db *gorm.DB, threshold time.Duration) {
+	if threshold <= 0 {
+		return
+	}
+	start := func(scope *gorm.Scope) {
+		scope.InstanceSet(startedAtKey, time.Now())
+	}
+	finish := func(scope *gorm.Scope) {
+		startedAt, ok := scope.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+		if elapsed := time.Since(startedAt.(time.Time)); elapsed > threshold {
+			log.Printf("Slow query (%s) from %s: %s [%s]", elapsed, repositoryCaller(), scope.SQL, argsSummary(scope.SQLVars))
+		}
+	}
+
+	callback := db.Callback()
+	callback.Create().Before("gorm:create").Register("librarium:slow_query_start", start)
+	callback.Create().After("gorm:create").Register("librarium:slow_query_finish", finish)
+	callback.Update().Before("gorm:update").Register("librarium:slow_query_start", start)
+	callback.Update().After("gorm:update").Register("librarium:slow_query_finish", finish)
+	callback.Delete().Before("gorm:delete").Register("librarium:slow_query_start", start)
+	callback.Delete().After("gorm:delete").Register("librarium:slow_query_finish", finish)
+	callback.Query().Before("gorm:query").Register("librarium:slow_query_start", start)
+	callback.Query().After("gorm:query").Register("librarium:slow_query_finish", finish)
+	callback.RowQuery().Before("gorm:row_query").Register("librarium:slow_query_start", start)
+	callback.RowQuery().After("gorm:row_query").Register("librarium:slow_query_finish", finish)
+}
+
+// repositoryCaller walks the stack looking for the controller method that
+// triggered the query. The controllers use value receivers, so the frames look
+// like relational.bookController.FindAll, and the queries run inside a
+// transaction come from a closure like relational.bookController.DeleteMany.func1
+func repositoryCaller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		name := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		if strings.HasPrefix(name, "relational.") && (strings.Contains(name, "Controller.") || strings.Contains(name, "Controller).")) {
+			return closureSuffix.ReplaceAllString(name, "")
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// argsSummary only reports the types of the arguments, we don't want to leak
+// personal data like emails into the logs
+func argsSummary(args []interface{}) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return fmt.Sprintf("%d args: %s", len(args), strings.Join(types, ", "))
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSlowQueryLogsTheRepositoryMethod(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	conn, mock := newMockConnection(t)
+	registerSlowQueryLogger(conn.DB(), time.Nanosecond)
+	mock.ExpectQuery(`SELECT \* FROM "books"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "books" SET "deleted_at"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	NewStorage(conn).Books().FindAll()
+	NewStorage(conn).Books().DeleteMany([]string{"1"})
+
+	if !strings.Contains(logs.String(), "from relational.bookController.FindAll: SELECT") {
+		t.Errorf("Should log the FindAll method as the caller but got %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "from relational.bookController.DeleteMany: UPDATE") {
+		t.Errorf("Should log the DeleteMany method without the closure suffix but got %s", logs.String())
+	}
+	if strings.Contains(logs.String(), "from unknown") {
+		t.Errorf("Every query should have a caller but got %s", logs.String())
+	}
+}
+
+func TestSlowQueryDisabled(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	conn, mock := newMockConnection(t)
+	registerSlowQueryLogger(conn.DB(), 0)
+	mock.ExpectQuery(`SELECT \* FROM "books"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	NewStorage(conn).Books().FindAll()
+	if strings.Contains(logs.String(), "Slow query") {
+		t.Errorf("A zero threshold shouldn't log anything but got %s", logs.String())
+	}
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"github.com/lib/pq"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+)
+
+const (
+	uniqueViolation = "23505"
+)
+
+// NewStorage builds the gorm repositories on top of the postgres connection
+func NewStorage(conn *Connection) repository.Storage {
+	return relational.NewStorage(conn, isUniqueViolation)
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,349 @@
+package relational
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type bookController struct {
+	db Database
+}
+
+type Book struct {
+	gorm.Model
+	Title            string
+	ISBN             string
+	Price            float64
+	Language         string
+	OriginalLanguage string
+	Location         string
+	SupplierID       uint
+	UserID           uint
+}
+
+// GetID returns an empty id for the books not stored yet, so they are
+// treated as new books when saving them
+func (b Book) GetID() string {
+	if b.ID == 0 {
+		return ""
+	}
+	return fmt.Sprint(b.ID)
+}
+
+func (b Book) GetTitle() string {
+	return b.Title
+}
+
+func (b Book) GetISBN() string {
+	return b.ISBN
+}
+
+func (b Book) GetPrice() float64 {
+	return b.Price
+}
+
+func (b Book) GetLanguage() string {
+	return b.Language
+}
+
+func (b Book) GetOriginalLanguage() string {
+	return b.OriginalLanguage
+}
+
+func (b Book) GetLocation() string {
+	return b.Location
+}
+
+func (b Book) GetSupplierID() string {
+	if b.SupplierID == 0 {
+		return ""
+	}
+	return fmt.Sprint(b.SupplierID)
+}
+
+// GetUser only knows the id of the user that has the book rented
+// TODO need to be able to get this User from a connection into database
+func (b Book) GetUser() *model.User {
+	if b.UserID == 0 {
+		return nil
+	}
+	return model.NewUser(strconv.FormatUint(uint64(b.UserID), 10), "", "", "")
+}
+
+func (b Book) GetCreatedAt() time.Time {
+	return b.CreatedAt
+}
+
+func (b Book) GetUpdatedAt() time.Time {
+	return b.UpdatedAt
+}
+
+// BookHistory keeps the fields changed on an update of a book as a JSON
+// object, see model.DiffBooks
+type BookHistory struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	BookID    uint   `gorm:"index"`
+	Changes   string `gorm:"type:text"`
+}
+
+func (BookHistory) TableName() string {
+	return "book_history"
+}
+
+func NewBookController(db Database) *bookController {
+	return &bookController{
+		db: db,
+	}
+}
+
+func (r bookController) FindAll() ([]model.Book, error) {
+	var fetchedBooks []Book
+	if err := r.db.ReadDB().Order("created_at desc, id desc").Find(&fetchedBooks).Error; err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func toBooks(fetchedBooks []Book) []model.Book {
+	books := make([]model.Book, len(fetchedBooks))
+	for i, book := range fetchedBooks {
+		books[i] = book
+	}
+	return books
+}
+
+func (r bookController) Count() (int, error) {
+	var count int
+	if err := r.db.ReadDB().Model(&Book{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r bookController) FindByID(id string) (model.Book, error) {
+	bookID, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+	var book Book
+	if err := r.db.DB().First(&book, "id = ?", bookID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+// FindByIDs skips the ids that aren't numbers, no book can have them
+func (r bookController) FindByIDs(ids []string) ([]model.Book, error) {
+	numericIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if numericID, err := strconv.ParseUint(id, 10, 64); err == nil {
+			numericIDs = append(numericIDs, numericID)
+		}
+	}
+	if len(numericIDs) == 0 {
+		return []model.Book{}, nil
+	}
+	var fetchedBooks []Book
+	err := r.db.DB().
+		Where("id IN (?)", numericIDs).
+		Order("created_at desc, id desc").
+		Find(&fetchedBooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func (r bookController) FindByISBN(ISBN string) (model.Book, error) {
+	var book Book
+	if err := r.db.DB().Where("isbn = ?", ISBN).First(&book).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+func (r bookController) FindByLanguage(language string) ([]model.Book, error) {
+	var fetchedBooks []Book
+	err := r.db.ReadDB().
+		Where("lower(trim(language)) = ?", language).
+		Order("created_at desc, id desc").
+		Find(&fetchedBooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func (r bookController) CountByLanguage() (map[string]int, error) {
+	var rows []struct {
+		Language string
+		Books    int
+	}
+	err := r.db.ReadDB().Model(&Book{}).
+		Select("lower(trim(language)) AS language, count(*) AS books").
+		Where("trim(language) <> ''").
+		Group("lower(trim(language))").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Language] = row.Books
+	}
+	return counts, nil
+}
+
+func (r bookController) FindBySupplier(supplierID string) ([]model.Book, error) {
+	id, err := strconv.ParseUint(supplierID, 10, 64)
+	if err != nil || id == 0 {
+		return []model.Book{}, nil
+	}
+	var fetchedBooks []Book
+	err = r.db.ReadDB().
+		Where("supplier_id = ?", id).
+		Order("created_at desc, id desc").
+		Find(&fetchedBooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func (r bookController) FindAddedSince(since time.Time) ([]model.Book, error) {
+	var fetchedBooks []Book
+	err := r.db.ReadDB().
+		Where("created_at >= ?", since).
+		Order("created_at desc, id desc").
+		Find(&fetchedBooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func (r bookController) Save(book model.Book) error {
+	supplierID, err := parseSupplierID(book.GetSupplierID())
+	if err != nil {
+		return err
+	}
+	if book.GetID() == "" {
+		return r.db.DB().Create(&Book{
+			Title:            book.GetTitle(),
+			ISBN:             book.GetISBN(),
+			Price:            book.GetPrice(),
+			Language:         book.GetLanguage(),
+			OriginalLanguage: book.GetOriginalLanguage(),
+			Location:         book.GetLocation(),
+			SupplierID:       supplierID,
+		}).Error
+	}
+
+	id, err := strconv.ParseUint(book.GetID(), 10, 64)
+	if err != nil || id == 0 {
+		return domainerr.Validation("Book with id: %s is not valid", book.GetID())
+	}
+	// The stored book is read on the same transaction to record the fields
+	// changed, the update always refreshes the updated_at
+	return r.db.DB().Transaction(func(tx *gorm.DB) error {
+		var stored Book
+		if err := tx.First(&stored, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return domainerr.NotFound("Book with id: %s not found", book.GetID())
+			}
+			return err
+		}
+		// Only update the book fields, saving the whole struct would also
+		// overwrite the CreatedAt with an empty value
+		err := tx.Model(&Book{Model: gorm.Model{ID: stored.ID}}).Updates(map[string]interface{}{
+			"title":             book.GetTitle(),
+			"isbn":              book.GetISBN(),
+			"price":             book.GetPrice(),
+			"language":          book.GetLanguage(),
+			"original_language": book.GetOriginalLanguage(),
+			"location":          book.GetLocation(),
+			"supplier_id":       supplierID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		fields := model.DiffBooks(stored, book)
+		if len(fields) == 0 {
+			return nil
+		}
+		changes, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&BookHistory{BookID: stored.ID, Changes: string(changes)}).Error
+	})
+}
+
+func (r bookController) FindHistory(id string) ([]*model.BookChange, error) {
+	bookID, ok := parseID(id)
+	if !ok {
+		return []*model.BookChange{}, nil
+	}
+	var fetchedHistory []BookHistory
+	if err := r.db.ReadDB().Where("book_id = ?", bookID).Order("id").Find(&fetchedHistory).Error; err != nil {
+		return nil, err
+	}
+	history := make([]*model.BookChange, len(fetchedHistory))
+	for i, change := range fetchedHistory {
+		fields := map[string]model.FieldChange{}
+		if err := json.Unmarshal([]byte(change.Changes), &fields); err != nil {
+			return nil, err
+		}
+		history[i] = model.NewBookChange(fmt.Sprint(change.BookID), fields, change.CreatedAt)
+	}
+	return history, nil
+}
+
+// parseSupplierID returns 0 for the books without supplier
+func parseSupplierID(supplierID string) (uint, error) {
+	if supplierID == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(supplierID, 10, 64)
+	if err != nil || id == 0 {
+		return 0, domainerr.Validation("Supplier with id: %s is not valid", supplierID)
+	}
+	return uint(id), nil
+}
+
+// Delete sets the deleted_at itself instead of using the gorm soft delete,
+// so the updated_at is also refreshed
+func (r bookController) Delete(id string) error {
+	bookID, ok := parseID(id)
+	if !ok {
+		return nil
+	}
+	return r.db.DB().Model(&Book{}).Where("id = ?", bookID).Update("deleted_at", gorm.NowFunc()).Error
+}
+
+// DeleteMany only removes the books that aren't rented, when a book was
+// rented or removed since it was selected the transaction is rolled back
+func (r bookController) DeleteMany(ids []string) error {
+	return r.db.DB().Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Book{}).Where("id IN (?) AND COALESCE(user_id, 0) = 0", ids).Update("deleted_at", gorm.NowFunc())
+		if res.Error != nil {
+			return res.Error
+		} else if res.RowsAffected != int64(len(ids)) {
+			return domainerr.Conflict("Only %d of %d books could be removed, the others are rented or no longer exist", res.RowsAffected, len(ids))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,268 @@
+package relational
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+var (
+	bookColumns = []string{"id", "created_at", "updated_at", "deleted_at", "title", "isbn", "price", "language", "original_language", "location", "supplier_id", "user_id"}
+)
+
+func TestBookFindAll(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "books" .*ORDER BY created_at desc, id desc`).
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+
+	books, err := NewBookController(conn).FindAll()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 {
+		t.Fatalf("Should be a list with one item but got %d items", len(books))
+	}
+	if books[0].GetID() != "1" || books[0].GetTitle() != "Test Title" || books[0].GetPrice() != 34.4 {
+		t.Errorf("Should get the book with id 1 but got %v", books[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookFindByID(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+
+	book, err := NewBookController(conn).FindByID("1")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if book.GetISBN() != "testIsbn" {
+		t.Errorf("Should get testIsbn but got %s", book.GetISBN())
+	}
+
+	book, err = NewBookController(conn).FindByID("2")
+	if book != nil || err != nil {
+		t.Errorf("No book should return a book and an error nil but got book %v err %v", book, err)
+	}
+	// The ids that aren't numbers don't reach the database, postgres would
+	// fail the query
+	book, err = NewBookController(conn).FindByID("notANumber")
+	if book != nil || err != nil {
+		t.Errorf("A not valid id should return a book and an error nil but got book %v err %v", book, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookFindByISBN(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "books" .*isbn = \$1`).
+		WithArgs("testIsbn").
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "books" .*isbn = \$1`).
+		WithArgs("noIsbn").
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+
+	book, err := NewBookController(conn).FindByISBN("testIsbn")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if book.GetID() != "1" {
+		t.Errorf("Should get the book with id 1 but got %s", book.GetID())
+	}
+
+	book, err = NewBookController(conn).FindByISBN("noIsbn")
+	if book != nil || err != nil {
+		t.Errorf("No book should return a book and an error nil but got book %v err %v", book, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookSaveNew(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "books"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := NewBookController(conn).Save(Book{Title: "Test Title", ISBN: "testIsbn", Price: 34.4, Language: "en", Location: "A-12"})
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookSaveExisting(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Another Test Title", "Another testIsbn", 35.5, "es", "en", "A-12", 4, 0))
+	mock.ExpectExec(`UPDATE "books" SET .*"isbn" = \$1, "language" = \$2, "location" = \$3, "original_language" = \$4, "price" = \$5, "supplier_id" = \$6, "title" = \$7, "updated_at" = \$8 .*"id" = \$9`).
+		WithArgs("Another testIsbn", "es", "B-3", "en", 35.5, 4, "Another Test Title", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "book_history"`).
+		WithArgs(sqlmock.AnyArg(), 1, `{"location":{"from":"A-12","to":"B-3"}}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	book := Book{Title: "Another Test Title", ISBN: "Another testIsbn", Price: 35.5, Language: "es", OriginalLanguage: "en", Location: "B-3", SupplierID: 4}
+	book.ID = 1
+	if err := NewBookController(conn).Save(book); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookSaveNotFound(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+	mock.ExpectRollback()
+
+	book := Book{Title: "Test Title", ISBN: "testIsbn", Price: 34.4}
+	book.ID = 2
+	err := NewBookController(conn).Save(book)
+	if !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookFindHistory(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "book_history" .*book_id = \$1\) ORDER BY "id"`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "book_id", "changes"}).
+			AddRow(1, time.Now(), 1, `{"location":{"from":"A-12","to":"B-3"}}`))
+
+	history, err := NewBookController(conn).FindHistory("1")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(history) != 1 || history[0].GetFields()["location"].To != "B-3" {
+		t.Errorf("Should get the location change but got %v", history)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookSaveNotValidID(t *testing.T) {
+	conn, _ := newMockConnection(t)
+	for _, id := range []string{"notANumber", "0"} {
+		err := NewBookController(conn).Save(fakeBook{id: id})
+		if !errors.Is(err, domainerr.ErrValidation) {
+			t.Errorf("Should be a validation error for id %s but got %v", id, err)
+		}
+	}
+}
+
+func TestBookDelete(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "books" SET "deleted_at" = \$1, "updated_at" = \$2 .*id = \$3`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewBookController(conn).Delete("1"); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+type fakeBook struct {
+	Book
+	id string
+}
+
+func (f fakeBook) GetID() string {
+	return f.id
+}
+
+func TestBookCount(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "books"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := NewBookController(conn).Count()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Should count 3 books but got %d", count)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookDeleteMany(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "books" SET "deleted_at" = \$1, "updated_at" = \$2 .*id IN \(\$3,\$4\) AND COALESCE\(user_id, 0\) = 0`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "1", "2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	err := NewBookController(conn).DeleteMany([]string{"1", "2"})
+	if !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error when a book is missing but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookFindByIDs(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id IN \(\$1,\$2\).* ORDER BY created_at desc, id desc`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+
+	books, err := NewBookController(conn).FindByIDs([]string{"1", "notANumber", "2"})
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 || books[0].GetID() != "1" {
+		t.Errorf("Should get the existing book with a single query but got %v", books)
+	}
+
+	books, err = NewBookController(conn).FindByIDs([]string{"notANumber"})
+	if len(books) != 0 || err != nil {
+		t.Errorf("Without valid ids there is nothing to query but got books %v err %v", books, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,142 @@
+package relational
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type stocktakeController struct {
+	db Database
+}
+
+// Stocktake is open while the ClosedAt is null
+type Stocktake struct {
+	gorm.Model
+	Location string
+	ClosedAt *time.Time
+}
+
+type StocktakeScan struct {
+	ID          uint `gorm:"primary_key"`
+	CreatedAt   time.Time
+	StocktakeID uint `gorm:"index"`
+	Code        string
+	Location    string
+}
+
+func NewStocktakeController(db Database) *stocktakeController {
+	return &stocktakeController{
+		db: db,
+	}
+}
+
+func (r stocktakeController) FindAll() ([]*model.Stocktake, error) {
+	var fetchedStocktakes []Stocktake
+	if err := r.db.ReadDB().Order("created_at desc, id desc").Find(&fetchedStocktakes).Error; err != nil {
+		return nil, err
+	}
+	stocktakes := make([]*model.Stocktake, len(fetchedStocktakes))
+	for i, stocktake := range fetchedStocktakes {
+		stocktakes[i] = stocktake.toModel()
+	}
+	return stocktakes, nil
+}
+
+// FindByID reads from the primary, the stocktake is checked before adding
+// the scans or closing it
+func (r stocktakeController) FindByID(id string) (*model.Stocktake, error) {
+	stocktakeID, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+	var stocktake Stocktake
+	if err := r.db.DB().First(&stocktake, "id = ?", stocktakeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return stocktake.toModel(), nil
+}
+
+func (r stocktakeController) Create(stocktake *model.Stocktake) (string, error) {
+	stored := Stocktake{Location: stocktake.GetLocation()}
+	stored.CreatedAt = stocktake.GetStartedAt()
+	if err := r.db.DB().Create(&stored).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprint(stored.ID), nil
+}
+
+func (r stocktakeController) Close(id string, closedAt time.Time) error {
+	stocktakeID, err := parseStocktakeID(id)
+	if err != nil {
+		return err
+	}
+	res := r.db.DB().Model(&Stocktake{}).Where("id = ?", stocktakeID).Update("closed_at", closedAt)
+	if res.Error != nil {
+		return res.Error
+	} else if res.RowsAffected == 0 {
+		return domainerr.NotFound("Stocktake with id: %s not found", id)
+	}
+	return nil
+}
+
+// AddScans stores all the scans or none of them, so a batch sent again
+// after an error is not counted twice
+func (r stocktakeController) AddScans(id string, scans []*model.StocktakeScan) error {
+	stocktakeID, err := parseStocktakeID(id)
+	if err != nil {
+		return err
+	}
+	return r.db.DB().Transaction(func(tx *gorm.DB) error {
+		for _, scan := range scans {
+			err := tx.Create(&StocktakeScan{
+				CreatedAt:   scan.GetScannedAt(),
+				StocktakeID: stocktakeID,
+				Code:        scan.GetCode(),
+				Location:    scan.GetLocation(),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r stocktakeController) FindScans(id string) ([]*model.StocktakeScan, error) {
+	stocktakeID, ok := parseID(id)
+	if !ok {
+		return []*model.StocktakeScan{}, nil
+	}
+	var fetchedScans []StocktakeScan
+	if err := r.db.ReadDB().Where("stocktake_id = ?", stocktakeID).Order("id").Find(&fetchedScans).Error; err != nil {
+		return nil, err
+	}
+	scans := make([]*model.StocktakeScan, len(fetchedScans))
+	for i, scan := range fetchedScans {
+		scans[i] = model.NewStocktakeScan(scan.Code, scan.Location, scan.CreatedAt)
+	}
+	return scans, nil
+}
+
+func (s Stocktake) toModel() *model.Stocktake {
+	var closedAt time.Time
+	if s.ClosedAt != nil {
+		closedAt = *s.ClosedAt
+	}
+	return model.NewStocktake(fmt.Sprint(s.ID), s.Location, s.CreatedAt, closedAt)
+}
+
+func parseStocktakeID(id string) (uint, error) {
+	stocktakeID, ok := parseID(id)
+	if !ok {
+		return 0, domainerr.Validation("Stocktake with id: %s is not valid", id)
+	}
+	return stocktakeID, nil
+}
@@ -0,0 +1,115 @@
+package relational
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+)
+
+const (
+	// uniqueEmailIndex ignores the case and the removed users, so a removed
+	// user doesn't block the email until it's restored
+	uniqueEmailIndex = "CREATE UNIQUE INDEX IF NOT EXISTS idx_users_lower_email ON users (lower(email)) WHERE deleted_at IS NULL"
+)
+
+// Database is the connection the controllers run the queries on, the lists
+// and counts can be served from a replica with ReadDB, but the lookups done
+// before a write use DB since a lagging replica could miss a recent change
+type Database interface {
+	DB() *gorm.DB
+	ReadDB() *gorm.DB
+}
+
+// UniqueViolation tells if an error comes from a unique index, every driver
+// reports it in its own way
+type UniqueViolation func(err error) bool
+
+type storage struct {
+	users      *userController
+	books      *bookController
+	tags       *tagController
+	suppliers  *supplierController
+	stocktakes *stocktakeController
+}
+
+// NewStorage builds the repositories shared by all the databases gorm talks
+// to, only the unique violation check depends on the driver
+func NewStorage(db Database, isUniqueViolation UniqueViolation) *storage {
+	return &storage{
+		users:      NewUserController(db, isUniqueViolation),
+		books:      NewBookController(db),
+		tags:       NewTagController(db),
+		suppliers:  NewSupplierController(db),
+		stocktakes: NewStocktakeController(db),
+	}
+}
+
+func (s *storage) Users() repository.UserRepository {
+	return s.users
+}
+
+func (s *storage) Books() repository.BookRepository {
+	return s.books
+}
+
+func (s *storage) Tags() repository.TagRepository {
+	return s.tags
+}
+
+func (s *storage) Suppliers() repository.SupplierRepository {
+	return s.suppliers
+}
+
+func (s *storage) Stocktakes() repository.StocktakeRepository {
+	return s.stocktakes
+}
+
+// parseID tells if the id can belong to a stored row, the ids are positive
+// numbers. The ids come from the request path and any other value makes
+// postgres fail the query, which would count as a failure of the database
+func parseID(id string) (uint, bool) {
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || numericID == 0 {
+		return 0, false
+	}
+	return uint(numericID), true
+}
+
+// Migrate creates or updates the tables of all the models and the indexes
+// gorm doesn't know how to create
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&User{}, &Book{}, &BookTag{}, &Supplier{}, &BookHistory{}, &Stocktake{}, &StocktakeScan{}).Error; err != nil {
+		return err
+	}
+	return createUniqueEmailIndex(db)
+}
+
+// createUniqueEmailIndex can't create the index while there are users with
+// the same email in a different case, those users are reported and the index
+// is left for a later migration, once they are fixed
+func createUniqueEmailIndex(db *gorm.DB) error {
+	var duplicated []User
+	err := db.Where("lower(email) IN (?)", db.Model(&User{}).
+		Select("lower(email)").
+		Group("lower(email)").
+		Having("count(*) > 1").
+		SubQuery()).
+		Order("lower(email), id").
+		Find(&duplicated).Error
+	if err != nil {
+		return err
+	}
+	if len(duplicated) > 0 {
+		conflicts := make([]string, len(duplicated))
+		for i, user := range duplicated {
+			conflicts[i] = fmt.Sprintf("%s (id %d)", user.Email, user.ID)
+		}
+		log.Printf("The unique email index is not created, these users have the same email ignoring the case: %s", strings.Join(conflicts, ", "))
+		return nil
+	}
+	return db.Exec(uniqueEmailIndex).Error
+}
@@ -0,0 +1,112 @@
+package relational
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+type mockDatabase struct {
+	db      *gorm.DB
+	replica *gorm.DB
+}
+
+func (m mockDatabase) DB() *gorm.DB {
+	return m.db
+}
+
+func (m mockDatabase) ReadDB() *gorm.DB {
+	if m.replica != nil {
+		return m.replica
+	}
+	return m.db
+}
+
+// newMockConnection builds a postgres database backed by sqlmock, so the
+// controllers can be tested without a running database
+func newMockConnection(t *testing.T) (Database, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	return mockDatabase{db: db}, mock
+}
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error creating the mock but got %v", err)
+	}
+	db, err := gorm.Open("postgres", sqlDB)
+	if err != nil {
+		t.Fatalf("Shouldn't be an error opening gorm but got %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db, mock
+}
+
+func neverUniqueViolation(err error) bool {
+	return false
+}
+
+func TestLookupsBeforeWritesUseThePrimary(t *testing.T) {
+	primary, mock := newMockDB(t)
+	replica, replicaMock := newMockDB(t)
+	db := mockDatabase{db: primary, replica: replica}
+	mock.ExpectQuery(`SELECT \* FROM "books" .*isbn = \$1`).
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+	mock.ExpectQuery(`SELECT \* FROM "books" .*id = \$1`).
+		WillReturnRows(sqlmock.NewRows(bookColumns))
+	mock.ExpectQuery(`SELECT \* FROM "users" .*lower\(email\) = lower\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectQuery(`SELECT \* FROM "users" .*deleted_at IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	replicaMock.ExpectQuery(`SELECT \* FROM "books"`).
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+
+	storage := NewStorage(db, neverUniqueViolation)
+	storage.Books().FindByISBN("testIsbn")
+	storage.Books().FindByID("1")
+	storage.Users().FindByEmail("test@test.com")
+	storage.Users().FindDeletedByID("12")
+	if books, _ := storage.Books().FindAll(); len(books) != 1 {
+		t.Errorf("The list should come from the replica but got %d books", len(books))
+	}
+
+	for _, m := range []sqlmock.Sqlmock{mock, replicaMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestMigrateReportsDuplicatedEmails(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Shouldn't be an error opening sqlite but got %v", err)
+	}
+	defer db.Close()
+	db.AutoMigrate(&User{})
+	db.Create(&User{Email: "test@test.com"})
+	duplicated := User{Email: "Test@Test.com"}
+	db.Create(&duplicated)
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("The duplicated emails shouldn't fail the migration but got %v", err)
+	}
+	if db.Dialect().HasIndex("users", "idx_users_lower_email") {
+		t.Error("The index shouldn't be created while there are duplicated emails")
+	}
+
+	db.Delete(&duplicated)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if !db.Dialect().HasIndex("users", "idx_users_lower_email") {
+		t.Error("The index should be created once the duplicated emails are fixed")
+	}
+}
@@ -0,0 +1,109 @@
+package relational
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type supplierController struct {
+	db Database
+}
+
+// Supplier keeps the categories as a JSON list, they are only read together
+// with the supplier
+type Supplier struct {
+	gorm.Model
+	Name       string
+	Contact    string
+	Categories string `gorm:"type:text"`
+}
+
+func NewSupplierController(db Database) *supplierController {
+	return &supplierController{
+		db: db,
+	}
+}
+
+func (r supplierController) FindAll() ([]*model.Supplier, error) {
+	var fetchedSuppliers []Supplier
+	if err := r.db.ReadDB().Order("name, id").Find(&fetchedSuppliers).Error; err != nil {
+		return nil, err
+	}
+	suppliers := make([]*model.Supplier, len(fetchedSuppliers))
+	for i, supplier := range fetchedSuppliers {
+		s, err := supplier.toModel()
+		if err != nil {
+			return nil, err
+		}
+		suppliers[i] = s
+	}
+	return suppliers, nil
+}
+
+func (r supplierController) FindByID(id string) (*model.Supplier, error) {
+	supplierID, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+	var supplier Supplier
+	if err := r.db.DB().First(&supplier, "id = ?", supplierID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return supplier.toModel()
+}
+
+func (r supplierController) Save(supplier *model.Supplier) error {
+	categories, err := json.Marshal(supplier.GetCategories())
+	if err != nil {
+		return err
+	}
+	if supplier.GetID() == "" {
+		return r.db.DB().Create(&Supplier{
+			Name:       supplier.GetName(),
+			Contact:    supplier.GetContact(),
+			Categories: string(categories),
+		}).Error
+	}
+
+	id, err := strconv.ParseUint(supplier.GetID(), 10, 64)
+	if err != nil || id == 0 {
+		return domainerr.Validation("Supplier with id: %s is not valid", supplier.GetID())
+	}
+	res := r.db.DB().Model(&Supplier{Model: gorm.Model{ID: uint(id)}}).Updates(map[string]interface{}{
+		"name":       supplier.GetName(),
+		"contact":    supplier.GetContact(),
+		"categories": string(categories),
+	})
+	if res.Error != nil {
+		return res.Error
+	} else if res.RowsAffected == 0 {
+		return domainerr.NotFound("Supplier with id: %s not found", supplier.GetID())
+	}
+	return nil
+}
+
+func (r supplierController) Delete(id string) error {
+	supplierID, ok := parseID(id)
+	if !ok {
+		return nil
+	}
+	return r.db.DB().Where("id = ?", supplierID).Delete(&Supplier{}).Error
+}
+
+func (s Supplier) toModel() (*model.Supplier, error) {
+	var categories []string
+	if s.Categories != "" {
+		if err := json.Unmarshal([]byte(s.Categories), &categories); err != nil {
+			return nil, err
+		}
+	}
+	return model.NewSupplier(fmt.Sprint(s.ID), s.Name, s.Contact, categories), nil
+}
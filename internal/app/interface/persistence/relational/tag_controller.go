@@ -0,0 +1,61 @@
+package relational
+
+import (
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type tagController struct {
+	db Database
+}
+
+type BookTag struct {
+	BookID uint   `gorm:"primary_key;auto_increment:false"`
+	Tag    string `gorm:"primary_key;index"`
+}
+
+func NewTagController(db Database) *tagController {
+	return &tagController{
+		db: db,
+	}
+}
+
+func (r tagController) AddTag(bookID, tag string) error {
+	id, err := parseBookID(bookID)
+	if err != nil {
+		return err
+	}
+	bookTag := BookTag{BookID: id, Tag: tag}
+	return r.db.DB().Where(bookTag).FirstOrCreate(&bookTag).Error
+}
+
+func (r tagController) RemoveTag(bookID, tag string) error {
+	id, err := parseBookID(bookID)
+	if err != nil {
+		return err
+	}
+	return r.db.DB().Where("book_id = ? AND tag = ?", id, tag).Delete(&BookTag{}).Error
+}
+
+// FindBooksByTag joins the tags with the books, so the removed books are
+// skipped by gorm like on any other query
+func (r tagController) FindBooksByTag(tag string) ([]model.Book, error) {
+	var fetchedBooks []Book
+	err := r.db.ReadDB().
+		Joins("JOIN book_tags ON book_tags.book_id = books.id").
+		Where("book_tags.tag = ?", tag).
+		Order("books.created_at desc, books.id desc").
+		Find(&fetchedBooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return toBooks(fetchedBooks), nil
+}
+
+func parseBookID(bookID string) (uint, error) {
+	id, ok := parseID(bookID)
+	if !ok {
+		return 0, domainerr.Validation("Book with id: %s is not valid", bookID)
+	}
+	return id, nil
+}
@@ -0,0 +1,28 @@
+package relational
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFindBooksByTag(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT "books".\* FROM "books" JOIN book_tags ON book_tags.book_id = books.id WHERE .*book_tags.tag = \$1.* ORDER BY books.created_at desc, books.id desc`).
+		WithArgs("classics").
+		WillReturnRows(sqlmock.NewRows(bookColumns).
+			AddRow(2, time.Now(), time.Now(), nil, "Other Title", "otherIsbn", 12.5, "en", "", "A-1", 0, 0).
+			AddRow(1, time.Now(), time.Now(), nil, "Test Title", "testIsbn", 34.4, "en", "", "A-12", 0, 0))
+
+	books, err := NewTagController(conn).FindBooksByTag("classics")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 2 || books[0].GetID() != "2" || books[1].GetID() != "1" {
+		t.Errorf("Should get the tagged books with a single query but got %v", books)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
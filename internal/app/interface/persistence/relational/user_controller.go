@@ -0,0 +1,128 @@
+package relational
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+
+	"github.com/jinzhu/gorm"
+)
+
+type userController struct {
+	db                Database
+	isUniqueViolation UniqueViolation
+}
+
+type User struct {
+	gorm.Model
+	Email    string
+	Name     string
+	LastName string
+	Books    []Book
+}
+
+// toModel maps the stored row into the domain user
+func (u User) toModel() *model.User {
+	return model.NewUser(strconv.FormatUint(uint64(u.ID), 10), u.Email, u.Name, u.LastName)
+}
+
+func NewUserController(db Database, isUniqueViolation UniqueViolation) *userController {
+	return &userController{
+		db:                db,
+		isUniqueViolation: isUniqueViolation,
+	}
+}
+
+func (r userController) FindAll() ([]*model.User, error) {
+	var fetchedUsers []User
+	if err := r.db.ReadDB().Order("last_name, name, id").Find(&fetchedUsers).Error; err != nil {
+		return nil, err
+	}
+	users := make([]*model.User, len(fetchedUsers))
+	for i, user := range fetchedUsers {
+		users[i] = user.toModel()
+	}
+	return users, nil
+}
+
+func (r userController) Count() (int, error) {
+	var count int
+	if err := r.db.ReadDB().Model(&User{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r userController) FindByEmail(email string) (*model.User, error) {
+	var user User
+	if err := r.db.DB().Where("lower(email) = lower(?)", email).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user.toModel(), nil
+}
+
+func (r userController) FindByID(id string) (*model.User, error) {
+	log.Printf("Finding a user by ID: %s", id)
+	userID, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+	var user User
+	if err := r.db.DB().First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user.toModel(), nil
+}
+
+func (r userController) Save(user *model.User) error {
+	err := r.db.DB().Save(&User{
+		Email:    user.GetEmail(),
+		Name:     user.GetName(),
+		LastName: user.GetLastName(),
+	}).Error
+	// Two requests with the same email at once both pass the duplicated
+	// check, the unique index stops the second one
+	if err != nil && r.isUniqueViolation(err) {
+		return domainerr.Conflict("%s already exists", user.GetEmail())
+	}
+	return err
+}
+
+func (r userController) Delete(user *model.User) error {
+	log.Printf("User ID: %s", user.GetID())
+	return r.db.DB().Where("id = ?", user.GetID()).Delete(&User{}).Error
+}
+
+// FindDeletedByID needs Unscoped since gorm skips the soft deleted rows
+func (r userController) FindDeletedByID(id string) (*model.User, error) {
+	userID, ok := parseID(id)
+	if !ok {
+		return nil, nil
+	}
+	var user User
+	if err := r.db.DB().Unscoped().Where("id = ? AND deleted_at IS NOT NULL", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user.toModel(), nil
+}
+
+func (r userController) Restore(user *model.User) error {
+	err := r.db.DB().Unscoped().Model(&User{}).Where("id = ?", user.GetID()).Update("deleted_at", nil).Error
+	// Another user can take the email between the duplicated check and the
+	// restore, the unique index stops it like on Save
+	if err != nil && r.isUniqueViolation(err) {
+		return domainerr.Conflict("%s already exists", user.GetEmail())
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package relational
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+var (
+	userColumns = []string{"id", "created_at", "updated_at", "deleted_at", "email", "name", "last_name"}
+)
+
+func TestUserFindAll(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "users" .*ORDER BY last_name, name, id`).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(12, time.Now(), time.Now(), nil, "test@test.com", "testName", "testLastName").
+			AddRow(13, time.Now(), time.Now(), nil, "other@test.com", "otherName", "otherLastName"))
+
+	users, err := NewUserController(conn, neverUniqueViolation).FindAll()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Should be a list with two items but got %d items", len(users))
+	}
+	if users[0].GetID() != "12" {
+		t.Errorf("The id should be 12 but got %s", users[0].GetID())
+	}
+	if users[1].GetEmail() != "other@test.com" {
+		t.Errorf("The email should be other@test.com but got %s", users[1].GetEmail())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserFindByEmail(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "users" .*lower\(email\) = lower\(\$1\)`).
+		WithArgs("test@test.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(12, time.Now(), time.Now(), nil, "test@test.com", "testName", "testLastName"))
+	mock.ExpectQuery(`SELECT \* FROM "users" .*lower\(email\) = lower\(\$1\)`).
+		WithArgs("noUser@test.com").
+		WillReturnRows(sqlmock.NewRows(userColumns))
+
+	user, err := NewUserController(conn, neverUniqueViolation).FindByEmail("test@test.com")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if user.GetID() != "12" || user.GetName() != "testName" || user.GetLastName() != "testLastName" {
+		t.Errorf("Should get the user with id 12 but got %v", user)
+	}
+
+	user, err = NewUserController(conn, neverUniqueViolation).FindByEmail("noUser@test.com")
+	if user != nil || err != nil {
+		t.Errorf("No user should return a user and an error nil but got user %v err %v", user, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserFindByID(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT \* FROM "users" .*id = \$1`).
+		WithArgs(12).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(12, time.Now(), time.Now(), nil, "test@test.com", "testName", "testLastName"))
+	mock.ExpectQuery(`SELECT \* FROM "users" .*id = \$1`).
+		WithArgs(13).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+
+	user, err := NewUserController(conn, neverUniqueViolation).FindByID("12")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if user.GetID() != "12" || user.GetEmail() != "test@test.com" {
+		t.Errorf("Should get the user with id 12 but got %v", user)
+	}
+
+	user, err = NewUserController(conn, neverUniqueViolation).FindByID("13")
+	if user != nil || err != nil {
+		t.Errorf("No user should return a user and an error nil but got user %v err %v", user, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserSave(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "users"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "test@test.com", "testName", "testLastName").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+
+	err := NewUserController(conn, neverUniqueViolation).Save(model.NewUser("", "test@test.com", "testName", "testLastName"))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserDelete(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "deleted_at"=\$1 .*id = \$2`).
+		WithArgs(sqlmock.AnyArg(), "12").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := NewUserController(conn, neverUniqueViolation).Delete(model.NewUser("12", "test@test.com", "testName", "testLastName"))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserCount(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := NewUserController(conn, neverUniqueViolation).Count()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Should count 3 users but got %d", count)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserRestore(t *testing.T) {
+	conn, mock := newMockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "deleted_at" = \$1, "updated_at" = \$2 WHERE \(id = \$3\)`).
+		WithArgs(nil, sqlmock.AnyArg(), "12").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := NewUserController(conn, neverUniqueViolation).Restore(model.NewUser("12", "test@test.com", "testName", "testLastName"))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"log"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+)
+
+type client struct {
+	path string
+}
+
+type Connection struct {
+	conn *gorm.DB
+}
+
+// NewClient creates a client for an embedded database stored on the given
+// path, it's meant for small libraries that don't want to run a postgres
+func NewClient(path string) *client {
+	return &client{
+		path: path,
+	}
+}
+
+// Connect opens the database and creates the tables, there is no migration
+// script for the embedded database
+func (c *client) Connect() *Connection {
+	db, err := gorm.Open("sqlite3", c.path)
+	if err != nil {
+		log.Panicf("Error trying to open the sqlite database: %v", err)
+	}
+	// sqlite only allows one writer at a time, and every new connection to
+	// an in memory database would get an empty one
+	db.DB().SetMaxOpenConns(1)
+	if err := relational.Migrate(db); err != nil {
+		log.Panicf("Error trying to migrate the sqlite database: %v", err)
+	}
+	return &Connection{
+		conn: db,
+	}
+}
+
+func (c *Connection) DB() *gorm.DB {
+	return c.conn
+}
+
+// ReadDB is the same database, sqlite has no replicas
+func (c *Connection) ReadDB() *gorm.DB {
+	return c.conn
+}
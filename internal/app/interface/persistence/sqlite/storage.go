@@ -0,0 +1,17 @@
+package sqlite
+
+import (
+	"github.com/mattn/go-sqlite3"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+)
+
+// NewStorage builds the gorm repositories on top of the sqlite database
+func NewStorage(conn *Connection) repository.Storage {
+	return relational.NewStorage(conn, isUniqueViolation)
+}
+
+func isUniqueViolation(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	return ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
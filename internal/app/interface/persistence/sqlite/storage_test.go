@@ -0,0 +1,274 @@
+package sqlite_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/relational"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/sqlite"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestUserRoundTrip(t *testing.T) {
+	users := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Users()
+
+	if err := users.Save(model.NewUser("", "test@test.com", "testName", "testLastName")); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if err := users.Save(model.NewUser("", "Test@Test.com", "otherName", "otherLastName")); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error for the same email in other case but got %v", err)
+	}
+	user, err := users.FindByEmail("TEST@test.com")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if user == nil || user.GetName() != "testName" {
+		t.Fatalf("Should find the stored user but got %v", user)
+	}
+
+	found, err := users.FindByID(user.GetID())
+	if err != nil || found == nil {
+		t.Fatalf("Should find the user by id but got user %v err %v", found, err)
+	}
+
+	if err := users.Delete(found); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	all, _ := users.FindAll()
+	if len(all) != 0 {
+		t.Errorf("After remove the user the list should be empty but got %d items", len(all))
+	}
+
+	deleted, err := users.FindDeletedByID(found.GetID())
+	if err != nil || deleted == nil {
+		t.Fatalf("Should find the removed user but got user %v err %v", deleted, err)
+	}
+	if err := users.Restore(deleted); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if restored, _ := users.FindByID(found.GetID()); restored == nil {
+		t.Error("Should find the user after restoring it")
+	}
+}
+
+func TestUserRestoreConflict(t *testing.T) {
+	users := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Users()
+	users.Save(model.NewUser("", "test@test.com", "testName", "testLastName"))
+	user, _ := users.FindByEmail("test@test.com")
+	users.Delete(user)
+	// The email is free again while the user is removed
+	if err := users.Save(model.NewUser("", "Test@test.com", "otherName", "otherLastName")); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+
+	if err := users.Restore(user); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error restoring a taken email but got %v", err)
+	}
+}
+
+func TestBookRoundTrip(t *testing.T) {
+	books := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Books()
+
+	if err := books.Save(relational.Book{Title: "Test Title", ISBN: "testIsbn", Price: 34.4}); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	book, err := books.FindByISBN("testIsbn")
+	if err != nil || book == nil {
+		t.Fatalf("Should find the stored book but got book %v err %v", book, err)
+	}
+
+	updated := relational.Book{Title: "Another Test Title", ISBN: "testIsbn", Price: 35.5}
+	stored := book.(relational.Book)
+	updated.ID = stored.ID
+	if err := books.Save(updated); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	book, _ = books.FindByID(book.GetID())
+	if book.GetTitle() != "Another Test Title" || book.GetPrice() != 35.5 {
+		t.Errorf("Should get the updated book but got %v", book)
+	}
+	all, _ := books.FindAll()
+	if len(all) != 1 {
+		t.Errorf("Updating shouldn't create a new book but got %d items", len(all))
+	}
+
+	if err := books.Delete(book.GetID()); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	book, err = books.FindByID(book.GetID())
+	if book != nil || err != nil {
+		t.Errorf("No book should return a book and an error nil but got book %v err %v", book, err)
+	}
+}
+
+func TestBookHistory(t *testing.T) {
+	books := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Books()
+	books.Save(relational.Book{Title: "Test Title", ISBN: "testIsbn", Price: 34.4, Location: "A-1"})
+	book, _ := books.FindByISBN("testIsbn")
+	stored := book.(relational.Book)
+
+	time.Sleep(time.Millisecond)
+	moved := stored
+	moved.Location = "B-2"
+	if err := books.Save(moved); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	book, _ = books.FindByID(stored.GetID())
+	if !book.(relational.Book).UpdatedAt.After(stored.UpdatedAt) {
+		t.Errorf("The update should change the updated_at but got %v", book.(relational.Book).UpdatedAt)
+	}
+	if err := books.Save(moved); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+
+	history, err := books.FindHistory(stored.GetID())
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Should only record the update that changed the book but got %d changes", len(history))
+	}
+	change := history[0].GetFields()
+	if len(change) != 1 || change["location"] != (model.FieldChange{From: "A-1", To: "B-2"}) {
+		t.Errorf("Should record the location change but got %v", change)
+	}
+}
+
+func TestTagRoundTrip(t *testing.T) {
+	storage := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect())
+	storage.Books().Save(relational.Book{Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+	book, _ := storage.Books().FindByISBN("testIsbn")
+
+	for i := 0; i < 2; i++ {
+		if err := storage.Tags().AddTag(book.GetID(), "classics"); err != nil {
+			t.Fatalf("Shouldn't be an error but got %v", err)
+		}
+	}
+	storage.Books().Save(relational.Book{Title: "Other Title", ISBN: "otherIsbn", Price: 12.5})
+	removed, _ := storage.Books().FindByISBN("otherIsbn")
+	storage.Tags().AddTag(removed.GetID(), "classics")
+	storage.Books().Delete(removed.GetID())
+
+	books, err := storage.Tags().FindBooksByTag("classics")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 || books[0].GetID() != book.GetID() {
+		t.Errorf("Should get only the tagged book once, without the removed one, but got %v", books)
+	}
+
+	if err := storage.Tags().RemoveTag(book.GetID(), "classics"); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	books, _ = storage.Tags().FindBooksByTag("classics")
+	if len(books) != 0 {
+		t.Errorf("After remove the tag the list should be empty but got %v", books)
+	}
+}
+
+func TestBookLanguages(t *testing.T) {
+	books := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Books()
+	books.Save(relational.Book{Title: "Test Title", ISBN: "testIsbn", Language: "EN"})
+	books.Save(relational.Book{Title: "Other Title", ISBN: "otherIsbn", Language: "en"})
+	books.Save(relational.Book{Title: "Otro Titulo", ISBN: "otroIsbn", Language: "es"})
+	books.Save(relational.Book{Title: "No Language", ISBN: "noLanguageIsbn"})
+	removed, _ := books.FindByISBN("otroIsbn")
+	books.Delete(removed.GetID())
+
+	english, err := books.FindByLanguage("en")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(english) != 2 || english[0].GetTitle() != "Other Title" {
+		t.Errorf("Should get the english books ignoring the case, newest first, but got %v", english)
+	}
+
+	counts, err := books.CountByLanguage()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(counts) != 1 || counts["en"] != 2 {
+		t.Errorf("Should only count the english books but got %v", counts)
+	}
+}
+
+func TestSupplierRoundTrip(t *testing.T) {
+	storage := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect())
+	suppliers := storage.Suppliers()
+	if err := suppliers.Save(model.NewSupplier("", "Books & Co", "sales@books.com", []string{"poetry", "history"})); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	all, err := suppliers.FindAll()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(all) != 1 || all[0].GetName() != "Books & Co" || len(all[0].GetCategories()) != 2 {
+		t.Fatalf("Should get the stored supplier but got %v", all)
+	}
+
+	err = suppliers.Save(model.NewSupplier(all[0].GetID(), "Books & Co", "orders@books.com", []string{"poetry"}))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	supplier, _ := suppliers.FindByID(all[0].GetID())
+	if supplier.GetContact() != "orders@books.com" || len(supplier.GetCategories()) != 1 {
+		t.Errorf("Should get the supplier updated but got %v", supplier)
+	}
+	if err := suppliers.Save(model.NewSupplier("99", "Unknown", "", nil)); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+
+	books := storage.Books()
+	books.Save(relational.Book{Title: "Test Title", ISBN: "testIsbn", SupplierID: 1})
+	books.Save(relational.Book{Title: "Other Title", ISBN: "otherIsbn"})
+	supplied, err := books.FindBySupplier(supplier.GetID())
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(supplied) != 1 || supplied[0].GetSupplierID() != supplier.GetID() {
+		t.Errorf("Should get the supplied book but got %v", supplied)
+	}
+
+	suppliers.Delete(supplier.GetID())
+	if supplier, _ := suppliers.FindByID(supplier.GetID()); supplier != nil {
+		t.Errorf("The removed supplier shouldn't be found but got %v", supplier)
+	}
+}
+
+func TestStocktakeRoundTrip(t *testing.T) {
+	stocktakes := sqlite.NewStorage(sqlite.NewClient(":memory:").Connect()).Stocktakes()
+	id, err := stocktakes.Create(model.NewStocktake("", "A-1", time.Now(), time.Time{}))
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	stocktake, err := stocktakes.FindByID(id)
+	if err != nil || stocktake == nil {
+		t.Fatalf("Should find the stored stocktake but got stocktake %v err %v", stocktake, err)
+	}
+	if stocktake.GetLocation() != "A-1" || stocktake.IsClosed() {
+		t.Errorf("Should get an open stocktake of A-1 but got %v", stocktake)
+	}
+
+	stocktakes.AddScans(id, []*model.StocktakeScan{model.NewStocktakeScan("1", "A-1", time.Now())})
+	stocktakes.AddScans(id, []*model.StocktakeScan{model.NewStocktakeScan("9788420633114", "A-2", time.Now())})
+	scans, err := stocktakes.FindScans(id)
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(scans) != 2 || scans[0].GetCode() != "1" || scans[1].GetLocation() != "A-2" {
+		t.Errorf("Should get the scans in the order they were added but got %v", scans)
+	}
+
+	if err := stocktakes.Close(id, time.Now()); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	stocktake, _ = stocktakes.FindByID(id)
+	if !stocktake.IsClosed() {
+		t.Errorf("Should get the stocktake closed but got %v", stocktake)
+	}
+	if err := stocktakes.Close("99", time.Now()); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package sru
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+)
+
+const (
+	maximumRecords = 10
+)
+
+// Book is a book found on the external catalog, it's only a draft so it has
+// no id, price or user
+type Book struct {
+	Title            string
+	ISBN             string
+	Language         string
+	OriginalLanguage string
+}
+
+func (b Book) GetID() string {
+	return ""
+}
+
+func (b Book) GetTitle() string {
+	return b.Title
+}
+
+func (b Book) GetISBN() string {
+	return b.ISBN
+}
+
+func (b Book) GetPrice() float64 {
+	return 0
+}
+
+func (b Book) GetLanguage() string {
+	return b.Language
+}
+
+func (b Book) GetOriginalLanguage() string {
+	return b.OriginalLanguage
+}
+
+func (b Book) GetLocation() string {
+	return ""
+}
+
+func (b Book) GetUser() *model.User {
+	return nil
+}
+
+func (b Book) GetSupplierID() string {
+	return ""
+}
+
+func (b Book) GetCreatedAt() time.Time {
+	return time.Time{}
+}
+
+func (b Book) GetUpdatedAt() time.Time {
+	return time.Time{}
+}
+
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for an SRU server, like the Library of Congress
+// one, asking for the records in MARCXML
+func NewClient(baseURL string, httpClient *http.Client) *client {
+	return &client{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+	}
+}
+
+type searchRetrieveResponse struct {
+	Records []marcRecord `xml:"records>record>recordData>record"`
+}
+
+type marcRecord struct {
+	ControlFields []controlField `xml:"controlfield"`
+	DataFields    []dataField    `xml:"datafield"`
+}
+
+type controlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type dataField struct {
+	Tag       string     `xml:"tag,attr"`
+	Subfields []subfield `xml:"subfield"`
+}
+
+type subfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// cqlTerm quotes the value, so the user input can't add clauses to the
+// query, the quotes and backslashes inside are escaped as CQL expects
+func cqlTerm(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func (c *client) Search(ISBN, title string) ([]model.Book, error) {
+	var clauses []string
+	if ISBN != "" {
+		clauses = append(clauses, "bath.isbn="+cqlTerm(ISBN))
+	}
+	if title != "" {
+		clauses = append(clauses, "dc.title="+cqlTerm(title))
+	}
+	query := url.Values{}
+	query.Set("version", "1.1")
+	query.Set("operation", "searchRetrieve")
+	query.Set("recordSchema", "marcxml")
+	query.Set("maximumRecords", fmt.Sprint(maximumRecords))
+	query.Set("query", strings.Join(clauses, " and "))
+
+	res, err := c.httpClient.Get(c.baseURL + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SRU server replied with status %d", res.StatusCode)
+	}
+
+	response := &searchRetrieveResponse{}
+	if err := xml.NewDecoder(res.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	books := make([]model.Book, 0, len(response.Records))
+	for _, record := range response.Records {
+		book := Book{
+			Title:            cleanTitle(record.subfield("245", "a") + " " + record.subfield("245", "b")),
+			ISBN:             cleanISBN(record.subfield("020", "a")),
+			Language:         record.language(),
+			OriginalLanguage: record.subfield("041", "h"),
+		}
+		if book.Title != "" {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+// subfield returns the first value of the given MARC field and subfield
+func (r marcRecord) subfield(tag, code string) string {
+	for _, field := range r.DataFields {
+		if field.Tag != tag {
+			continue
+		}
+		for _, sub := range field.Subfields {
+			if sub.Code == code {
+				return sub.Value
+			}
+		}
+	}
+	return ""
+}
+
+// language uses the 041 field and falls back to the positions 35-37 of the
+// 008 field, both hold MARC language codes
+func (r marcRecord) language() string {
+	if language := r.subfield("041", "a"); language != "" {
+		return language
+	}
+	for _, field := range r.ControlFields {
+		if field.Tag == "008" && len(field.Value) >= 38 {
+			return strings.TrimSpace(field.Value[35:38])
+		}
+	}
+	return ""
+}
+
+// cleanTitle removes the MARC punctuation at the end of the title parts,
+// like "The Go programming language /"
+func cleanTitle(title string) string {
+	parts := strings.Fields(title)
+	for i, part := range parts {
+		if part == "/" || part == ":" {
+			parts[i] = ""
+		}
+	}
+	return strings.TrimRight(strings.Join(strings.Fields(strings.Join(parts, " ")), " "), " /:;,.")
+}
+
+// cleanISBN removes the qualifiers, like "9780134190440 (pbk.)"
+func cleanISBN(ISBN string) string {
+	if fields := strings.Fields(ISBN); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package sru_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/app/interface/sru"
+)
+
+const (
+	sampleResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<zs:searchRetrieveResponse xmlns:zs="http://www.loc.gov/zing/srw/">
+  <zs:version>1.1</zs:version>
+  <zs:numberOfRecords>1</zs:numberOfRecords>
+  <zs:records>
+    <zs:record>
+      <zs:recordSchema>marcxml</zs:recordSchema>
+      <zs:recordData>
+        <record xmlns="http://www.loc.gov/MARC21/slim">
+          <controlfield tag="008">150420s2016    nju      b    001 0 eng  </controlfield>
+          <datafield tag="020" ind1=" " ind2=" ">
+            <subfield code="a">9780134190440 (pbk.)</subfield>
+          </datafield>
+          <datafield tag="245" ind1="1" ind2="4">
+            <subfield code="a">The Go programming language /</subfield>
+            <subfield code="c">Alan A.A. Donovan, Brian W. Kernighan.</subfield>
+          </datafield>
+        </record>
+      </zs:recordData>
+    </zs:record>
+  </zs:records>
+</zs:searchRetrieveResponse>`
+)
+
+func TestSearch(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(sampleResponse))
+	}))
+	defer server.Close()
+
+	books, err := sru.NewClient(server.URL, server.Client()).Search("9780134190440", "")
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if query != `bath.isbn="9780134190440"` {
+		t.Errorf("Should search by ISBN but got query %s", query)
+	}
+	if len(books) != 1 {
+		t.Fatalf("Should be a list with only one item but got %d items", len(books))
+	}
+	if books[0].GetTitle() != "The Go programming language" {
+		t.Errorf("Should get the clean title but got %q", books[0].GetTitle())
+	}
+	if books[0].GetISBN() != "9780134190440" {
+		t.Errorf("Should get the clean ISBN but got %q", books[0].GetISBN())
+	}
+	if books[0].GetLanguage() != "eng" {
+		t.Errorf("Should get the language from the 008 field but got %q", books[0].GetLanguage())
+	}
+}
+
+func TestSearchQuotesTheTerms(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(sampleResponse))
+	}))
+	defer server.Close()
+
+	sru.NewClient(server.URL, server.Client()).Search(`978 or dc.title=x`, `The "Go" \ language`)
+	if query != `bath.isbn="978 or dc.title=x" and dc.title="The \"Go\" \\ language"` {
+		t.Errorf("Should quote and escape every term but got query %s", query)
+	}
+}
+
+func TestSearchServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := sru.NewClient(server.URL, server.Client()).Search("", "go"); err == nil {
+		t.Error("Should be an error when the server fails")
+	}
+}
@@ -1,17 +1,84 @@
 package usecase
 
 import (
+	"sort"
+	"strings"
+	"unicode"
+
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
 	"github.com/ramonmacias/librarium/internal/app/domain/repository"
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type BookInteractor interface {
 	ListBooks() ([]model.Book, error)
+	ListBooksByLanguage(language string) ([]model.Book, error)
+	LanguageFacets() ([]*LanguageFacet, error)
 	RegisterBook(book model.Book) error
 	UpdateBook(book model.Book) error
 	RemoveBook(id string) error
+	MoveBook(id, location string) error
+	ShelfList() ([]model.Book, error)
+	BulkRemoveBooks(criteria BulkRemovalCriteria, dryRun bool) (*BulkRemoval, error)
 	FindByID(id string) (model.Book, error)
+	ListDuplicates() ([]*DuplicatedBooks, error)
+	BookHistory(id string) ([]*model.BookChange, error)
+	Availability() ([]*BookAvailability, error)
+}
+
+// DuplicatedBooks groups the books that are probably the same one, Reason
+// tells if they share the ISBN or the title
+type DuplicatedBooks struct {
+	Reason string
+	Key    string
+	Books  []model.Book
+}
+
+// LanguageFacet counts the books written in a language
+type LanguageFacet struct {
+	Language string `json:"language"`
+	Books    int    `json:"books"`
+}
+
+// BookAvailability tells if a book is on the shelves or rented by a user
+type BookAvailability struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	ISBN     string `json:"isbn"`
+	Location string `json:"location"`
+	Status   string `json:"status"`
+}
+
+const (
+	StatusAvailable = "available"
+	StatusRented    = "rented"
+)
+
+// BulkRemovalCriteria selects the books to remove, by id, by language or
+// both
+type BulkRemovalCriteria struct {
+	IDs      []string
+	Language string
+}
+
+// BulkRemoval reports the books removed, or the ones that would be removed
+// on a dry run, and the ones skipped
+type BulkRemoval struct {
+	DryRun   bool
+	Removed  []model.Book
+	Rented   []model.Book
+	NotFound []string
+}
+
+// relocatedBook is a stored book with a new location
+type relocatedBook struct {
+	model.Book
+	location string
+}
+
+func (r relocatedBook) GetLocation() string {
+	return r.location
 }
 
 type bookInteractor struct {
@@ -34,7 +101,38 @@ func (b *bookInteractor) ListBooks() ([]model.Book, error) {
 	return books, nil
 }
 
+// ListBooksByLanguage returns the books written in the given language, the
+// codes are compared ignoring the case
+func (b *bookInteractor) ListBooksByLanguage(language string) ([]model.Book, error) {
+	return b.repo.FindByLanguage(normalizeLanguage(language))
+}
+
+// LanguageFacets counts the books per language, the most common first, the
+// books without language are not counted
+func (b *bookInteractor) LanguageFacets() ([]*LanguageFacet, error) {
+	counts, err := b.repo.CountByLanguage()
+	if err != nil {
+		return nil, err
+	}
+	facets := make([]*LanguageFacet, 0, len(counts))
+	for language, count := range counts {
+		facets = append(facets, &LanguageFacet{Language: language, Books: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Books != facets[j].Books {
+			return facets[i].Books > facets[j].Books
+		}
+		return facets[i].Language < facets[j].Language
+	})
+	return facets, nil
+}
+
+// RegisterBook adds a new book, the storage gives it the id. A book with an
+// id would update the stored one instead
 func (b *bookInteractor) RegisterBook(book model.Book) error {
+	if book.GetID() != "" {
+		return domainerr.Validation("A new book can't have an id")
+	}
 	if err := b.service.Duplicated(book.GetISBN()); err != nil {
 		return err
 	}
@@ -45,10 +143,239 @@ func (b *bookInteractor) UpdateBook(book model.Book) error {
 	return b.repo.Save(book)
 }
 
+// MoveBook changes the shelf where the book is kept
+func (b *bookInteractor) MoveBook(id, location string) error {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return domainerr.Validation("The location can't be empty")
+	}
+	book, err := b.repo.FindByID(id)
+	if err != nil {
+		return err
+	} else if book == nil {
+		return domainerr.NotFound("Book with id: %s not found", id)
+	}
+	return b.repo.Save(relocatedBook{Book: book, location: location})
+}
+
+// ShelfList returns the books sorted by location and title, the order to walk
+// the shelves, the books without location go at the end
+func (b *bookInteractor) ShelfList() ([]model.Book, error) {
+	books, err := b.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	sortByShelf(books)
+	return books, nil
+}
+
+// sortByShelf sorts the books by location and title, the books without
+// location go at the end
+func sortByShelf(books []model.Book) {
+	sort.SliceStable(books, func(i, j int) bool {
+		left, right := books[i].GetLocation(), books[j].GetLocation()
+		if (left == "") != (right == "") {
+			return right == ""
+		}
+		if left != right {
+			return left < right
+		}
+		return books[i].GetTitle() < books[j].GetTitle()
+	})
+}
+
 func (b *bookInteractor) RemoveBook(id string) error {
 	return b.repo.Delete(id)
 }
 
+// BulkRemoveBooks removes all the books matching the criteria at once, the
+// rented books are never removed. On a dry run nothing is removed, the
+// report tells what would happen. A book rented or removed between the
+// selection and the removal makes the whole removal fail with a conflict
+func (b *bookInteractor) BulkRemoveBooks(criteria BulkRemovalCriteria, dryRun bool) (*BulkRemoval, error) {
+	if len(criteria.IDs) == 0 && strings.TrimSpace(criteria.Language) == "" {
+		return nil, domainerr.Validation("Some ids or a language are needed to remove books")
+	}
+
+	removal := &BulkRemoval{DryRun: dryRun}
+	var requested []string
+	selected := map[string]bool{}
+	for _, id := range criteria.IDs {
+		if !selected[id] {
+			selected[id] = true
+			requested = append(requested, id)
+		}
+	}
+	var candidates []model.Book
+	if len(requested) > 0 {
+		books, err := b.repo.FindByIDs(requested)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]model.Book, len(books))
+		for _, book := range books {
+			found[book.GetID()] = book
+		}
+		for _, id := range requested {
+			if book, ok := found[id]; ok {
+				candidates = append(candidates, book)
+			} else {
+				removal.NotFound = append(removal.NotFound, id)
+			}
+		}
+	}
+	if criteria.Language != "" {
+		books, err := b.ListBooksByLanguage(criteria.Language)
+		if err != nil {
+			return nil, err
+		}
+		for _, book := range books {
+			if !selected[book.GetID()] {
+				selected[book.GetID()] = true
+				candidates = append(candidates, book)
+			}
+		}
+	}
+
+	var ids []string
+	for _, book := range candidates {
+		if book.GetUser() != nil {
+			removal.Rented = append(removal.Rented, book)
+			continue
+		}
+		removal.Removed = append(removal.Removed, book)
+		ids = append(ids, book.GetID())
+	}
+	if dryRun || len(ids) == 0 {
+		return removal, nil
+	}
+	if err := b.repo.DeleteMany(ids); err != nil {
+		return nil, err
+	}
+	return removal, nil
+}
+
 func (b *bookInteractor) FindByID(id string) (model.Book, error) {
 	return b.repo.FindByID(id)
 }
+
+// Availability lists every book with its status, a book rented by a user is
+// not available until it's returned
+func (b *bookInteractor) Availability() ([]*BookAvailability, error) {
+	books, err := b.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	availability := make([]*BookAvailability, len(books))
+	for i, book := range books {
+		status := StatusAvailable
+		if book.GetUser() != nil {
+			status = StatusRented
+		}
+		availability[i] = &BookAvailability{
+			ID:       book.GetID(),
+			Title:    book.GetTitle(),
+			ISBN:     book.GetISBN(),
+			Location: book.GetLocation(),
+			Status:   status,
+		}
+	}
+	return availability, nil
+}
+
+// BookHistory returns the changes made to the book, the oldest first
+func (b *bookInteractor) BookHistory(id string) ([]*model.BookChange, error) {
+	book, err := b.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	} else if book == nil {
+		return nil, domainerr.NotFound("Book with id: %s not found", id)
+	}
+	return b.repo.FindHistory(id)
+}
+
+// ListDuplicates groups the books by normalized ISBN and by normalized
+// title, the title groups with the same books as an ISBN group are skipped
+func (b *bookInteractor) ListDuplicates() ([]*DuplicatedBooks, error) {
+	books, err := b.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byISBN := groupBooks(books, "isbn", normalizeISBN)
+	reported := map[string]bool{}
+	for _, group := range byISBN {
+		reported[groupIDs(group.Books)] = true
+	}
+	duplicates := byISBN
+	for _, group := range groupBooks(books, "title", normalizeTitle) {
+		if !reported[groupIDs(group.Books)] {
+			duplicates = append(duplicates, group)
+		}
+	}
+	return duplicates, nil
+}
+
+func groupBooks(books []model.Book, reason string, normalize func(string) string) []*DuplicatedBooks {
+	groups := map[string][]model.Book{}
+	for _, book := range books {
+		value := book.GetISBN()
+		if reason == "title" {
+			value = book.GetTitle()
+		}
+		if key := normalize(value); key != "" {
+			groups[key] = append(groups[key], book)
+		}
+	}
+
+	var duplicates []*DuplicatedBooks
+	for key, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, &DuplicatedBooks{
+				Reason: reason,
+				Key:    key,
+				Books:  group,
+			})
+		}
+	}
+	// Keep the report stable between calls
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Key < duplicates[j].Key
+	})
+	return duplicates
+}
+
+func groupIDs(books []model.Book) string {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.GetID()
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// normalizeISBN removes the hyphens and spaces, so 978-0-13-235088-4 and
+// 9780132350884 are the same ISBN
+func normalizeISBN(ISBN string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, ISBN))
+}
+
+// normalizeTitle ignores the case, the punctuation and the extra spaces
+func normalizeTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
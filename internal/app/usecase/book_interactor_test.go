@@ -1,20 +1,27 @@
 package usecase_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ramonmacias/librarium/internal/app/domain/model"
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
 	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
 	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type FakeBookModel struct {
-	ID    string
-	Title string
-	ISBN  string
-	Price float64
-	User  *model.User
+	ID               string
+	Title            string
+	ISBN             string
+	Price            float64
+	Language         string
+	OriginalLanguage string
+	Location         string
+	SupplierID       string
+	User             *model.User
 }
 
 func (f FakeBookModel) GetID() string {
@@ -33,10 +40,34 @@ func (f FakeBookModel) GetPrice() float64 {
 	return f.Price
 }
 
+func (f FakeBookModel) GetLanguage() string {
+	return f.Language
+}
+
+func (f FakeBookModel) GetOriginalLanguage() string {
+	return f.OriginalLanguage
+}
+
+func (f FakeBookModel) GetLocation() string {
+	return f.Location
+}
+
 func (f FakeBookModel) GetUser() *model.User {
 	return f.User
 }
 
+func (f FakeBookModel) GetSupplierID() string {
+	return f.SupplierID
+}
+
+func (f FakeBookModel) GetCreatedAt() time.Time {
+	return time.Time{}
+}
+
+func (f FakeBookModel) GetUpdatedAt() time.Time {
+	return time.Time{}
+}
+
 var (
 	bookInteractor usecase.BookInteractor
 )
@@ -104,6 +135,25 @@ func RemovingBooks(t *testing.T) {
 	}
 }
 
+func TestRegisterBookWithID(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Rayuela", ISBN: "testIsbn"})
+
+	for _, id := range []string{"1", "2"} {
+		err := interactor.RegisterBook(FakeBookModel{ID: id, Title: "Another Title", ISBN: "anotherIsbn"})
+		if !errors.Is(err, domainerr.ErrValidation) {
+			t.Errorf("Should be a validation error for the id %s but got %v", id, err)
+		}
+	}
+	if book, _ := interactor.FindByID("1"); book.GetTitle() != "Rayuela" {
+		t.Errorf("The stored book shouldn't change but got %v", book)
+	}
+	if err := bookController.Save(FakeBookModel{ID: "2", Title: "Another Title"}); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error saving an unknown id but got %v", err)
+	}
+}
+
 func TestFindAndUpdateBook(t *testing.T) {
 	bookInteractor.RegisterBook(FakeBookModel{
 		Title: "Test Title",
@@ -147,3 +197,194 @@ func TestFindAndUpdateBook(t *testing.T) {
 		t.Errorf("Should get 34.4 but got %f", books[0].GetPrice())
 	}
 }
+
+func TestListDuplicatedBooks(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Clean Code", ISBN: "978-0-13-235088-4"})
+	bookController.Load(FakeBookModel{ID: "2", Title: "Clean code.", ISBN: "9780132350884"})
+	bookController.Load(FakeBookModel{ID: "3", Title: "The Go Programming  Language", ISBN: "9780134190440"})
+	bookController.Load(FakeBookModel{ID: "4", Title: "the go programming language", ISBN: "0134190440"})
+	bookController.Load(FakeBookModel{ID: "5", Title: "Refactoring", ISBN: "9780134757599"})
+
+	duplicates, err := interactor.ListDuplicates()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Fatalf("Should get two groups of duplicated books but got %d", len(duplicates))
+	}
+	if duplicates[0].Reason != "isbn" || duplicates[0].Key != "9780132350884" || len(duplicates[0].Books) != 2 {
+		t.Errorf("Should group the books with the same ISBN but got %v", duplicates[0])
+	}
+	if duplicates[1].Reason != "title" || duplicates[1].Key != "the go programming language" || len(duplicates[1].Books) != 2 {
+		t.Errorf("Should group the books with the same title but got %v", duplicates[1])
+	}
+}
+
+func TestListBooksNewestFirst(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	for _, ISBN := range []string{"first", "second", "third"} {
+		interactor.RegisterBook(FakeBookModel{Title: ISBN, ISBN: ISBN})
+		time.Sleep(time.Millisecond)
+	}
+
+	books, err := interactor.ListBooks()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	for i, ISBN := range []string{"third", "second", "first"} {
+		if books[i].GetISBN() != ISBN {
+			t.Errorf("The book in position %d should be %s but got %s", i, ISBN, books[i].GetISBN())
+		}
+	}
+}
+
+func TestListBooksByLanguage(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Save(FakeBookModel{Title: "Cien años de soledad", ISBN: "1", Language: "spa"})
+	bookController.Save(FakeBookModel{Title: "One Hundred Years of Solitude", ISBN: "2", Language: "eng", OriginalLanguage: "spa"})
+	bookController.Save(FakeBookModel{Title: "The Go Programming Language", ISBN: "3", Language: "ENG"})
+	bookController.Save(FakeBookModel{Title: "Unknown", ISBN: "4"})
+
+	books, err := interactor.ListBooksByLanguage("eng")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 2 {
+		t.Errorf("Should find the two english books ignoring the case but got %d", len(books))
+	}
+
+	facets, err := interactor.LanguageFacets()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(facets) != 2 {
+		t.Fatalf("Should be two languages but got %d", len(facets))
+	}
+	if facets[0].Language != "eng" || facets[0].Books != 2 || facets[1].Language != "spa" || facets[1].Books != 1 {
+		t.Errorf("Should count 2 eng and 1 spa books but got %v and %v", facets[0], facets[1])
+	}
+}
+
+func TestBulkRemoveBooks(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Cien años de soledad", Language: "spa"})
+	bookController.Load(FakeBookModel{ID: "2", Title: "Rayuela", Language: "spa", User: model.NewUser("u1", "test@test.com", "Test", "User")})
+	bookController.Load(FakeBookModel{ID: "3", Title: "The Go Programming Language", Language: "eng"})
+
+	if _, err := interactor.BulkRemoveBooks(usecase.BulkRemovalCriteria{}, false); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error without criteria but got %v", err)
+	}
+
+	criteria := usecase.BulkRemovalCriteria{IDs: []string{"3", "4"}, Language: "spa"}
+	removal, err := interactor.BulkRemoveBooks(criteria, true)
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if len(removal.Removed) != 2 || len(removal.Rented) != 1 || len(removal.NotFound) != 1 {
+		t.Errorf("Should report 2 removed, 1 rented and 1 not found but got %d, %d and %d", len(removal.Removed), len(removal.Rented), len(removal.NotFound))
+	}
+	if count, _ := bookController.Count(); count != 3 {
+		t.Errorf("A dry run shouldn't remove any book but got %d books", count)
+	}
+
+	if _, err := interactor.BulkRemoveBooks(criteria, false); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	books, _ := bookController.FindAll()
+	if len(books) != 1 || books[0].GetID() != "2" {
+		t.Errorf("Only the rented book should be left but got %v", books)
+	}
+
+	bookController.Load(FakeBookModel{ID: "5", Title: "Ficciones", Language: "spa"})
+	if err := bookController.DeleteMany([]string{"5", "2"}); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error removing a rented book but got %v", err)
+	}
+	if count, _ := bookController.Count(); count != 2 {
+		t.Errorf("A failed removal shouldn't remove any book but got %d books", count)
+	}
+}
+
+func TestAvailability(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Cien años de soledad", Location: "A-1"})
+	bookController.Load(FakeBookModel{ID: "2", Title: "Rayuela", User: model.NewUser("u1", "test@test.com", "Test", "User")})
+
+	availability, err := interactor.Availability()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	status := map[string]string{}
+	for _, book := range availability {
+		status[book.ID] = book.Status
+	}
+	if len(status) != 2 || status["1"] != usecase.StatusAvailable || status["2"] != usecase.StatusRented {
+		t.Errorf("The rented book shouldn't be available but got %v", status)
+	}
+}
+
+func TestBookHistory(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Rayuela", Price: 10, Location: "B-2"})
+
+	interactor.MoveBook("1", "A-1")
+	interactor.UpdateBook(FakeBookModel{ID: "1", Title: "Rayuela", Price: 12.5, Location: "A-1"})
+	interactor.UpdateBook(FakeBookModel{ID: "1", Title: "Rayuela", Price: 12.5, Location: "A-1"})
+
+	history, err := interactor.BookHistory("1")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Should record the two updates that changed the book but got %d changes", len(history))
+	}
+	if history[0].GetFields()["location"] != (model.FieldChange{From: "B-2", To: "A-1"}) || len(history[0].GetFields()) != 1 {
+		t.Errorf("Should record the move first but got %v", history[0].GetFields())
+	}
+	if history[1].GetFields()["price"] != (model.FieldChange{From: "10", To: "12.5"}) || len(history[1].GetFields()) != 1 {
+		t.Errorf("Should record the price change but got %v", history[1].GetFields())
+	}
+
+	if _, err := interactor.BookHistory("2"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+}
+
+func TestMoveBookAndShelfList(t *testing.T) {
+	bookController := memory.NewBookController()
+	interactor := usecase.NewBookInteractor(bookController, service.NewBookService(bookController))
+	bookController.Load(FakeBookModel{ID: "1", Title: "Rayuela", Location: "B-2"})
+	bookController.Load(FakeBookModel{ID: "2", Title: "Ficciones"})
+	bookController.Load(FakeBookModel{ID: "3", Title: "Aleph", Location: "B-2"})
+
+	if err := interactor.MoveBook("2", " A-1 "); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := interactor.MoveBook("4", "A-1"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if err := interactor.MoveBook("1", ""); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error but got %v", err)
+	}
+	book, _ := interactor.FindByID("2")
+	if book.GetLocation() != "A-1" || book.GetTitle() != "Ficciones" {
+		t.Errorf("Should only change the location but got %v", book)
+	}
+
+	bookController.Load(FakeBookModel{ID: "5", Title: "Unshelved"})
+	books, err := interactor.ShelfList()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	for i, id := range []string{"2", "3", "1", "5"} {
+		if books[i].GetID() != id {
+			t.Errorf("The book in position %d should be %s but got %s", i, id, books[i].GetID())
+		}
+	}
+}
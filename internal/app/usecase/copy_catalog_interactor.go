@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+// ExternalCatalog is a catalog outside the library, like the Library of
+// Congress, where we can copy the books data from
+type ExternalCatalog interface {
+	Search(ISBN, title string) ([]model.Book, error)
+}
+
+type CopyCatalogInteractor interface {
+	Drafts(ISBN, title string) ([]model.Book, error)
+}
+
+type copyCatalogInteractor struct {
+	catalog ExternalCatalog
+}
+
+func NewCopyCatalogInteractor(catalog ExternalCatalog) *copyCatalogInteractor {
+	return &copyCatalogInteractor{
+		catalog: catalog,
+	}
+}
+
+// Drafts looks for the books on the external catalog, the results are not
+// stored, they are meant to be reviewed before registering them
+func (c *copyCatalogInteractor) Drafts(ISBN, title string) ([]model.Book, error) {
+	ISBN, title = strings.TrimSpace(ISBN), strings.TrimSpace(title)
+	if ISBN == "" && title == "" {
+		return nil, domainerr.Validation("An ISBN or a title is needed to search the catalog")
+	}
+	return c.catalog.Search(ISBN, title)
+}
@@ -0,0 +1,41 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type FakeExternalCatalog struct {
+	ISBN  string
+	Title string
+}
+
+func (f *FakeExternalCatalog) Search(ISBN, title string) ([]model.Book, error) {
+	f.ISBN, f.Title = ISBN, title
+	return []model.Book{FakeBookModel{Title: "Test Title", ISBN: ISBN}}, nil
+}
+
+func TestCopyCatalogDrafts(t *testing.T) {
+	catalog := &FakeExternalCatalog{}
+	copyCatalogInteractor := usecase.NewCopyCatalogInteractor(catalog)
+
+	books, err := copyCatalogInteractor.Drafts(" testIsbn ", "")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 {
+		t.Errorf("Should be a list with only one item but got %d items", len(books))
+	}
+	if catalog.ISBN != "testIsbn" {
+		t.Errorf("Should search by the trimmed ISBN but got %q", catalog.ISBN)
+	}
+
+	_, err = copyCatalogInteractor.Drafts("", " ")
+	if !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error but got %v", err)
+	}
+}
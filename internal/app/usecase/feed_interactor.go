@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+)
+
+const (
+	newArrivalsPeriod = 30 * 24 * time.Hour
+)
+
+type FeedInteractor interface {
+	NewArrivals() ([]model.Book, error)
+	Invalidate()
+	// WithRepository returns an interactor on another repository that shares
+	// the same cache, like the one counting the calls of a request
+	WithRepository(repo repository.BookRepository) FeedInteractor
+}
+
+// feedInteractor keeps the new arrivals for a short time, the feed readers
+// poll the feed often and it only changes when books are added or removed
+type feedInteractor struct {
+	repo  repository.BookRepository
+	ttl   time.Duration
+	cache *arrivalsCache
+}
+
+type arrivalsCache struct {
+	mu       sync.Mutex
+	cached   []model.Book
+	cachedAt time.Time
+}
+
+func NewFeedInteractor(repo repository.BookRepository, ttl time.Duration) *feedInteractor {
+	return &feedInteractor{
+		repo:  repo,
+		ttl:   ttl,
+		cache: &arrivalsCache{},
+	}
+}
+
+func (f *feedInteractor) WithRepository(repo repository.BookRepository) FeedInteractor {
+	return &feedInteractor{
+		repo:  repo,
+		ttl:   f.ttl,
+		cache: f.cache,
+	}
+}
+
+// NewArrivals returns the books added on the last 30 days, the newest first
+func (f *feedInteractor) NewArrivals() ([]model.Book, error) {
+	f.cache.mu.Lock()
+	defer f.cache.mu.Unlock()
+
+	if f.cache.cached != nil && time.Since(f.cache.cachedAt) < f.ttl {
+		return f.cache.cached, nil
+	}
+	books, err := f.repo.FindAddedSince(time.Now().Add(-newArrivalsPeriod))
+	if err != nil {
+		return nil, err
+	}
+	f.cache.cached = books
+	f.cache.cachedAt = time.Now()
+	return f.cache.cached, nil
+}
+
+// Invalidate drops the cached new arrivals, it should be called after any
+// change on the books
+func (f *feedInteractor) Invalidate() {
+	f.cache.mu.Lock()
+	defer f.cache.mu.Unlock()
+
+	f.cache.cached = nil
+}
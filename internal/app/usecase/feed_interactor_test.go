@@ -0,0 +1,40 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+func TestNewArrivals(t *testing.T) {
+	bookController := memory.NewBookController()
+	bookController.Save(FakeBookModel{Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+	feedInteractor := usecase.NewFeedInteractor(bookController, time.Minute)
+
+	books, err := feedInteractor.NewArrivals()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 || books[0].GetTitle() != "Test Title" {
+		t.Errorf("Should get the book just added but got %v", books)
+	}
+
+	bookController.Save(FakeBookModel{Title: "Other Title", ISBN: "otherIsbn", Price: 12.5})
+	books, _ = feedInteractor.NewArrivals()
+	if len(books) != 1 {
+		t.Errorf("Should get the cached new arrivals but got %d books", len(books))
+	}
+
+	feedInteractor.Invalidate()
+	books, _ = feedInteractor.NewArrivals()
+	if len(books) != 2 {
+		t.Errorf("Should get both books after invalidating but got %d books", len(books))
+	}
+
+	books, _ = usecase.NewFeedInteractor(bookController, 0).NewArrivals()
+	if len(books) != 2 || books[0].GetTitle() != "Other Title" {
+		t.Errorf("Without cache should get both books, the newest first, but got %v", books)
+	}
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+)
+
+type StatsInteractor interface {
+	Overview() (*Overview, error)
+	Invalidate()
+	// WithRepositories returns an interactor on other repositories that
+	// shares the same cache, like the ones counting the calls of a request
+	WithRepositories(userRepo repository.UserRepository, bookRepo repository.BookRepository) StatsInteractor
+}
+
+type Overview struct {
+	Users int `json:"users"`
+	Books int `json:"books"`
+}
+
+// statsInteractor keeps the overview for a short time, so dashboards polling
+// it don't count the whole tables on every request
+type statsInteractor struct {
+	userRepo repository.UserRepository
+	bookRepo repository.BookRepository
+	ttl      time.Duration
+	cache    *overviewCache
+}
+
+type overviewCache struct {
+	mu       sync.Mutex
+	cached   *Overview
+	cachedAt time.Time
+}
+
+func NewStatsInteractor(userRepo repository.UserRepository, bookRepo repository.BookRepository, ttl time.Duration) *statsInteractor {
+	return &statsInteractor{
+		userRepo: userRepo,
+		bookRepo: bookRepo,
+		ttl:      ttl,
+		cache:    &overviewCache{},
+	}
+}
+
+func (s *statsInteractor) WithRepositories(userRepo repository.UserRepository, bookRepo repository.BookRepository) StatsInteractor {
+	return &statsInteractor{
+		userRepo: userRepo,
+		bookRepo: bookRepo,
+		ttl:      s.ttl,
+		cache:    s.cache,
+	}
+}
+
+func (s *statsInteractor) Overview() (*Overview, error) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if s.cache.cached != nil && time.Since(s.cache.cachedAt) < s.ttl {
+		return s.cache.cached, nil
+	}
+	users, err := s.userRepo.Count()
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.bookRepo.Count()
+	if err != nil {
+		return nil, err
+	}
+	s.cache.cached = &Overview{
+		Users: users,
+		Books: books,
+	}
+	s.cache.cachedAt = time.Now()
+	return s.cache.cached, nil
+}
+
+// Invalidate drops the cached overview, it should be called after any change
+// on the users or books
+func (s *statsInteractor) Invalidate() {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.cache.cached = nil
+}
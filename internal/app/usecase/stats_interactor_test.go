@@ -0,0 +1,41 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+)
+
+func TestStatsOverview(t *testing.T) {
+	bookController := memory.NewBookController()
+	statsInteractor := usecase.NewStatsInteractor(memory.NewUserController(), bookController, time.Minute)
+
+	overview, err := statsInteractor.Overview()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if overview.Users != 0 || overview.Books != 0 {
+		t.Errorf("Should be an empty overview but got %v", overview)
+	}
+
+	bookController.Save(FakeBookModel{Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+	overview, _ = statsInteractor.Overview()
+	if overview.Books != 0 {
+		t.Errorf("Should get the cached overview but got %d books", overview.Books)
+	}
+
+	statsInteractor.Invalidate()
+	overview, _ = statsInteractor.Overview()
+	if overview.Books != 1 {
+		t.Errorf("Should count the new book after invalidating but got %d books", overview.Books)
+	}
+
+	bookController.Save(FakeBookModel{Title: "Other Title", ISBN: "otherIsbn", Price: 12})
+	shared := statsInteractor.WithRepositories(memory.NewUserController(), memory.NewBookController())
+	overview, _ = shared.Overview()
+	if overview.Books != 1 {
+		t.Errorf("Should share the cached overview but got %d books", overview.Books)
+	}
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type StocktakeInteractor interface {
+	StartStocktake(location string) (*model.Stocktake, error)
+	ListStocktakes() ([]*model.Stocktake, error)
+	FindByID(id string) (*model.Stocktake, error)
+	ScanBooks(id, location string, codes []string) error
+	CloseStocktake(id string) error
+	Report(id string) (*StocktakeReport, error)
+}
+
+// StocktakeReport compares the scans of a stocktake with the catalog. The
+// missing books weren't scanned, the rented ones are not expected on the
+// shelves. The misplaced books were found on another shelf and the
+// unexpected scans don't match any book of the catalog
+type StocktakeReport struct {
+	Stocktake  *model.Stocktake
+	Scanned    int
+	Missing    []model.Book
+	Misplaced  []*MisplacedBook
+	Unexpected []*model.StocktakeScan
+}
+
+type MisplacedBook struct {
+	Book    model.Book
+	FoundAt string
+}
+
+type stocktakeInteractor struct {
+	repo     repository.StocktakeRepository
+	bookRepo repository.BookRepository
+}
+
+func NewStocktakeInteractor(repo repository.StocktakeRepository, bookRepo repository.BookRepository) *stocktakeInteractor {
+	return &stocktakeInteractor{
+		repo:     repo,
+		bookRepo: bookRepo,
+	}
+}
+
+// StartStocktake opens a stocktake of the books kept on the location, or of
+// the whole catalog when the location is empty
+func (s *stocktakeInteractor) StartStocktake(location string) (*model.Stocktake, error) {
+	id, err := s.repo.Create(model.NewStocktake("", strings.TrimSpace(location), time.Now(), time.Time{}))
+	if err != nil {
+		return nil, err
+	}
+	return s.FindByID(id)
+}
+
+func (s *stocktakeInteractor) ListStocktakes() ([]*model.Stocktake, error) {
+	return s.repo.FindAll()
+}
+
+func (s *stocktakeInteractor) FindByID(id string) (*model.Stocktake, error) {
+	stocktake, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	} else if stocktake == nil {
+		return nil, domainerr.NotFound("Stocktake with id: %s not found", id)
+	}
+	return stocktake, nil
+}
+
+// ScanBooks adds the codes read on the location to an open stocktake, the
+// scanners send them in batches while the audit goes on
+func (s *stocktakeInteractor) ScanBooks(id, location string, codes []string) error {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return domainerr.Validation("The location where the books were scanned can't be empty")
+	}
+	scans := make([]*model.StocktakeScan, 0, len(codes))
+	now := time.Now()
+	for _, code := range codes {
+		if code = strings.TrimSpace(code); code != "" {
+			scans = append(scans, model.NewStocktakeScan(code, location, now))
+		}
+	}
+	if len(scans) == 0 {
+		return domainerr.Validation("There are no codes to scan")
+	}
+	if err := s.checkOpen(id); err != nil {
+		return err
+	}
+	return s.repo.AddScans(id, scans)
+}
+
+func (s *stocktakeInteractor) CloseStocktake(id string) error {
+	if err := s.checkOpen(id); err != nil {
+		return err
+	}
+	return s.repo.Close(id, time.Now())
+}
+
+func (s *stocktakeInteractor) checkOpen(id string) error {
+	stocktake, err := s.FindByID(id)
+	if err != nil {
+		return err
+	} else if stocktake.IsClosed() {
+		return domainerr.Conflict("Stocktake with id: %s is closed", id)
+	}
+	return nil
+}
+
+// Report can be asked while the stocktake is open, it's final once it's
+// closed. A scan matches a book by id or by ISBN, the copies sharing an ISBN
+// are matched once each, the copy kept on the scanned location first. The
+// same id scanned twice is the same book, but an ISBN scanned more times
+// than copies there are is unexpected
+func (s *stocktakeInteractor) Report(id string) (*StocktakeReport, error) {
+	stocktake, err := s.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	scans, err := s.repo.FindScans(id)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.bookRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[string]model.Book{}
+	byISBN := map[string][]model.Book{}
+	for _, book := range books {
+		byID[book.GetID()] = book
+		if ISBN := normalizeISBN(book.GetISBN()); ISBN != "" {
+			byISBN[ISBN] = append(byISBN[ISBN], book)
+		}
+	}
+
+	report := &StocktakeReport{
+		Stocktake:  stocktake,
+		Scanned:    len(scans),
+		Missing:    []model.Book{},
+		Misplaced:  []*MisplacedBook{},
+		Unexpected: []*model.StocktakeScan{},
+	}
+	found := map[string]bool{}
+	for _, scan := range scans {
+		book, ok := byID[scan.GetCode()]
+		if ok && found[book.GetID()] {
+			continue
+		} else if !ok {
+			book = matchCopy(byISBN[normalizeISBN(scan.GetCode())], scan.GetLocation(), found)
+		}
+		if book == nil {
+			report.Unexpected = append(report.Unexpected, scan)
+			continue
+		}
+		found[book.GetID()] = true
+		if !sameLocation(book.GetLocation(), scan.GetLocation()) {
+			report.Misplaced = append(report.Misplaced, &MisplacedBook{Book: book, FoundAt: scan.GetLocation()})
+		}
+	}
+
+	for _, book := range books {
+		if found[book.GetID()] || book.GetUser() != nil {
+			continue
+		}
+		if stocktake.GetLocation() == "" || sameLocation(book.GetLocation(), stocktake.GetLocation()) {
+			report.Missing = append(report.Missing, book)
+		}
+	}
+	sortByShelf(report.Missing)
+	return report, nil
+}
+
+// matchCopy returns the first copy not found yet, the one kept on the
+// location if there is one
+func matchCopy(copies []model.Book, location string, found map[string]bool) model.Book {
+	var match model.Book
+	for _, book := range copies {
+		if found[book.GetID()] {
+			continue
+		}
+		if sameLocation(book.GetLocation(), location) {
+			return book
+		} else if match == nil {
+			match = book
+		}
+	}
+	return match
+}
+
+func sameLocation(left, right string) bool {
+	return strings.EqualFold(strings.TrimSpace(left), strings.TrimSpace(right))
+}
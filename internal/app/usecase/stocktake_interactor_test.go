@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestStocktakeReport(t *testing.T) {
+	bookController := memory.NewBookController()
+	stocktakeController := memory.NewStocktakeController()
+	bookController.Load(FakeBookModel{ID: "1", Title: "Rayuela", ISBN: "978-84-376-0494-7", Location: "A-1"})
+	bookController.Load(FakeBookModel{ID: "2", Title: "Rayuela", ISBN: "978-84-376-0494-7", Location: "A-1"})
+	bookController.Load(FakeBookModel{ID: "3", Title: "Ficciones", ISBN: "9788420633114", Location: "B-2"})
+	bookController.Load(FakeBookModel{ID: "4", Title: "Aleph", ISBN: "9788420633121", Location: "A-1"})
+	bookController.Load(FakeBookModel{ID: "5", Title: "Rented", ISBN: "9788420633138", Location: "A-1", User: model.NewUser("userID", "", "", "")})
+
+	stocktake, err := usecase.NewStocktakeInteractor(stocktakeController, bookController).StartStocktake(" A-1 ")
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if err := usecase.NewStocktakeInteractor(stocktakeController, bookController).ScanBooks(stocktake.GetID(), "A-1", []string{"9788437604947", " ", "3"}); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	// A new interactor resumes the stocktake from the storage
+	interactor := usecase.NewStocktakeInteractor(stocktakeController, bookController)
+	if err := interactor.ScanBooks(stocktake.GetID(), "a-1", []string{"3", "9788420633999"}); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+
+	report, err := interactor.Report(stocktake.GetID())
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if report.Scanned != 4 {
+		t.Errorf("Should count the 4 scans but got %d", report.Scanned)
+	}
+	if len(report.Missing) != 2 || report.Missing[0].GetID() == report.Missing[1].GetID() {
+		t.Fatalf("Should miss a copy of Rayuela and Aleph but got %v", report.Missing)
+	}
+	for _, book := range report.Missing {
+		if book.GetID() != "4" && book.GetID() != "1" && book.GetID() != "2" {
+			t.Errorf("Should only miss the books kept on A-1 and not rented but got %s", book.GetID())
+		}
+	}
+	if len(report.Misplaced) != 1 || report.Misplaced[0].Book.GetID() != "3" || report.Misplaced[0].FoundAt != "A-1" {
+		t.Errorf("Should find Ficciones misplaced on A-1 but got %v", report.Misplaced)
+	}
+	if len(report.Unexpected) != 1 || report.Unexpected[0].GetCode() != "9788420633999" {
+		t.Errorf("Should report the code not in the catalog but got %v", report.Unexpected)
+	}
+
+	if err := interactor.CloseStocktake(stocktake.GetID()); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := interactor.ScanBooks(stocktake.GetID(), "A-1", []string{"4"}); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error on a closed stocktake but got %v", err)
+	}
+	if err := interactor.CloseStocktake(stocktake.GetID()); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error closing it twice but got %v", err)
+	}
+}
+
+func TestStocktakeErrors(t *testing.T) {
+	interactor := usecase.NewStocktakeInteractor(memory.NewStocktakeController(), memory.NewBookController())
+	stocktake, _ := interactor.StartStocktake("")
+
+	if err := interactor.ScanBooks(stocktake.GetID(), " ", []string{"1"}); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error without location but got %v", err)
+	}
+	if err := interactor.ScanBooks(stocktake.GetID(), "A-1", []string{" "}); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error without codes but got %v", err)
+	}
+	if err := interactor.ScanBooks("noStocktakeID", "A-1", []string{"1"}); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if _, err := interactor.Report("noStocktakeID"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type SupplierInteractor interface {
+	ListSuppliers() ([]*model.Supplier, error)
+	FindByID(id string) (*model.Supplier, error)
+	RegisterSupplier(supplier *model.Supplier) error
+	UpdateSupplier(supplier *model.Supplier) error
+	RemoveSupplier(id string) error
+	SupplyBook(bookID, supplierID string) error
+	ListSuppliedBooks(supplierID string) ([]model.Book, error)
+}
+
+// suppliedBook is a stored book with a new supplier
+type suppliedBook struct {
+	model.Book
+	supplierID string
+}
+
+func (s suppliedBook) GetSupplierID() string {
+	return s.supplierID
+}
+
+type supplierInteractor struct {
+	repo     repository.SupplierRepository
+	bookRepo repository.BookRepository
+}
+
+func NewSupplierInteractor(repo repository.SupplierRepository, bookRepo repository.BookRepository) *supplierInteractor {
+	return &supplierInteractor{
+		repo:     repo,
+		bookRepo: bookRepo,
+	}
+}
+
+func (s *supplierInteractor) ListSuppliers() ([]*model.Supplier, error) {
+	return s.repo.FindAll()
+}
+
+func (s *supplierInteractor) FindByID(id string) (*model.Supplier, error) {
+	supplier, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	} else if supplier == nil {
+		return nil, domainerr.NotFound("Supplier with id: %s not found", id)
+	}
+	return supplier, nil
+}
+
+func (s *supplierInteractor) RegisterSupplier(supplier *model.Supplier) error {
+	supplier, err := normalizeSupplier(supplier)
+	if err != nil {
+		return err
+	}
+	return s.repo.Save(supplier)
+}
+
+func (s *supplierInteractor) UpdateSupplier(supplier *model.Supplier) error {
+	if _, err := s.FindByID(supplier.GetID()); err != nil {
+		return err
+	}
+	supplier, err := normalizeSupplier(supplier)
+	if err != nil {
+		return err
+	}
+	return s.repo.Save(supplier)
+}
+
+// RemoveSupplier keeps the suppliers that still supply books, the books
+// would point to a supplier that no longer exists
+func (s *supplierInteractor) RemoveSupplier(id string) error {
+	if _, err := s.FindByID(id); err != nil {
+		return err
+	}
+	books, err := s.bookRepo.FindBySupplier(id)
+	if err != nil {
+		return err
+	} else if len(books) > 0 {
+		return domainerr.Conflict("Supplier with id: %s still supplies %d books", id, len(books))
+	}
+	return s.repo.Delete(id)
+}
+
+// SupplyBook sets the supplier of the book, an empty supplier removes it
+func (s *supplierInteractor) SupplyBook(bookID, supplierID string) error {
+	supplierID = strings.TrimSpace(supplierID)
+	book, err := s.bookRepo.FindByID(bookID)
+	if err != nil {
+		return err
+	} else if book == nil {
+		return domainerr.NotFound("Book with id: %s not found", bookID)
+	}
+	if supplierID != "" {
+		if _, err := s.FindByID(supplierID); err != nil {
+			return err
+		}
+	}
+	return s.bookRepo.Save(suppliedBook{Book: book, supplierID: supplierID})
+}
+
+func (s *supplierInteractor) ListSuppliedBooks(supplierID string) ([]model.Book, error) {
+	if _, err := s.FindByID(supplierID); err != nil {
+		return nil, err
+	}
+	return s.bookRepo.FindBySupplier(supplierID)
+}
+
+// normalizeSupplier requires the name and keeps the categories in lower case
+// without repeating them, like the tags
+func normalizeSupplier(supplier *model.Supplier) (*model.Supplier, error) {
+	name := strings.TrimSpace(supplier.GetName())
+	if name == "" {
+		return nil, domainerr.Validation("The supplier name can't be empty")
+	}
+	categories := []string{}
+	seen := map[string]bool{}
+	for _, category := range supplier.GetCategories() {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		categories = append(categories, category)
+	}
+	return model.NewSupplier(supplier.GetID(), name, strings.TrimSpace(supplier.GetContact()), categories), nil
+}
@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestSupplierRegistry(t *testing.T) {
+	bookController := memory.NewBookController()
+	supplierInteractor := usecase.NewSupplierInteractor(memory.NewSupplierController(), bookController)
+
+	err := supplierInteractor.RegisterSupplier(model.NewSupplier("", " Books & Co ", "sales@books.com", []string{"Poetry", " poetry ", "", "History"}))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	suppliers, err := supplierInteractor.ListSuppliers()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(suppliers) != 1 {
+		t.Fatalf("Should be a list with one supplier but got %d items", len(suppliers))
+	}
+	supplier := suppliers[0]
+	if supplier.GetName() != "Books & Co" || !reflect.DeepEqual(supplier.GetCategories(), []string{"poetry", "history"}) {
+		t.Errorf("Should get the supplier normalized but got %s %v", supplier.GetName(), supplier.GetCategories())
+	}
+
+	err = supplierInteractor.UpdateSupplier(model.NewSupplier(supplier.GetID(), "Books & Co", "orders@books.com", nil))
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	supplier, _ = supplierInteractor.FindByID(supplier.GetID())
+	if supplier.GetContact() != "orders@books.com" || len(supplier.GetCategories()) != 0 {
+		t.Errorf("Should get the supplier updated but got %s %v", supplier.GetContact(), supplier.GetCategories())
+	}
+
+	bookController.Load(FakeBookModel{ID: "bookID", Title: "Test Title", ISBN: "testIsbn", Price: 34.4, Location: "A-1"})
+	if err := supplierInteractor.SupplyBook("bookID", supplier.GetID()); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	books, err := supplierInteractor.ListSuppliedBooks(supplier.GetID())
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 || books[0].GetID() != "bookID" || books[0].GetLocation() != "A-1" {
+		t.Errorf("Should be a list with the supplied book but got %v", books)
+	}
+
+	if err := supplierInteractor.RemoveSupplier(supplier.GetID()); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error while the supplier has books but got %v", err)
+	}
+	if err := supplierInteractor.SupplyBook("bookID", ""); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if err := supplierInteractor.RemoveSupplier(supplier.GetID()); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if _, err := supplierInteractor.FindByID(supplier.GetID()); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+}
+
+func TestSupplierRegistryErrors(t *testing.T) {
+	bookController := memory.NewBookController()
+	supplierInteractor := usecase.NewSupplierInteractor(memory.NewSupplierController(), bookController)
+	bookController.Load(FakeBookModel{ID: "bookID", Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+
+	if err := supplierInteractor.RegisterSupplier(model.NewSupplier("", "  ", "", nil)); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error but got %v", err)
+	}
+	if err := supplierInteractor.UpdateSupplier(model.NewSupplier("noSupplierID", "Books & Co", "", nil)); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if err := supplierInteractor.SupplyBook("bookID", "noSupplierID"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if err := supplierInteractor.SupplyBook("noBookID", ""); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+}
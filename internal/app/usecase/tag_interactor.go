@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/ramonmacias/librarium/internal/app/domain/model"
+	"github.com/ramonmacias/librarium/internal/app/domain/repository"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+type TagInteractor interface {
+	TagBook(bookID, tag string) error
+	UntagBook(bookID, tag string) error
+	ListBooksByTag(tag string) ([]model.Book, error)
+}
+
+type tagInteractor struct {
+	repo     repository.TagRepository
+	bookRepo repository.BookRepository
+}
+
+func NewTagInteractor(repo repository.TagRepository, bookRepo repository.BookRepository) *tagInteractor {
+	return &tagInteractor{
+		repo:     repo,
+		bookRepo: bookRepo,
+	}
+}
+
+func (t *tagInteractor) TagBook(bookID, tag string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	book, err := t.bookRepo.FindByID(bookID)
+	if err != nil {
+		return err
+	} else if book == nil {
+		return domainerr.NotFound("Book with id: %s not found", bookID)
+	}
+	return t.repo.AddTag(bookID, tag)
+}
+
+func (t *tagInteractor) UntagBook(bookID, tag string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	return t.repo.RemoveTag(bookID, tag)
+}
+
+func (t *tagInteractor) ListBooksByTag(tag string) ([]model.Book, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return t.repo.FindBooksByTag(tag)
+}
+
+// normalizeTag makes the tags case insensitive, so "Summer Reading" and
+// "summer reading" are the same tag
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", domainerr.Validation("Tag can't be empty")
+	}
+	return tag, nil
+}
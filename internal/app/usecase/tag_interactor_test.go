@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
+	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestTagBooks(t *testing.T) {
+	bookController := memory.NewBookController()
+	tagInteractor := usecase.NewTagInteractor(memory.NewTagController(bookController), bookController)
+	bookController.Load(FakeBookModel{ID: "bookID", Title: "Test Title", ISBN: "testIsbn", Price: 34.4})
+
+	if err := tagInteractor.TagBook("bookID", " Summer Reading "); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	books, err := tagInteractor.ListBooksByTag("summer reading")
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if len(books) != 1 || books[0].GetID() != "bookID" {
+		t.Errorf("Should be a list with the tagged book but got %v", books)
+	}
+
+	bookController.Load(FakeBookModel{ID: "otherBookID", Title: "Other Title", ISBN: "otherIsbn", Price: 12.5})
+	tagInteractor.TagBook("otherBookID", "summer reading")
+	bookController.Delete("otherBookID")
+	books, _ = tagInteractor.ListBooksByTag("summer reading")
+	if len(books) != 1 || books[0].GetID() != "bookID" {
+		t.Errorf("The removed books shouldn't be listed but got %v", books)
+	}
+
+	if err := tagInteractor.UntagBook("bookID", "SUMMER READING"); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	books, _ = tagInteractor.ListBooksByTag("summer reading")
+	if len(books) != 0 {
+		t.Errorf("After remove the tag the list should be empty but got %d items", len(books))
+	}
+}
+
+func TestTagBookErrors(t *testing.T) {
+	bookController := memory.NewBookController()
+	tagInteractor := usecase.NewTagInteractor(memory.NewTagController(bookController), bookController)
+
+	if err := tagInteractor.TagBook("noBookID", "classics"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if err := tagInteractor.TagBook("noBookID", "  "); !errors.Is(err, domainerr.ErrValidation) {
+		t.Errorf("Should be a validation error but got %v", err)
+	}
+}
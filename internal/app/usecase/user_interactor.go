@@ -6,12 +6,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/ramonmacias/librarium/internal/app/domain/repository"
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 type UserInteractor interface {
 	ListUser() ([]*User, error)
 	RegisterUser(email, name, lastName string) error
 	RemoveUser(id string) error
+	RestoreUser(id string) error
 	FindByID(id string) (*User, error)
 }
 
@@ -61,10 +63,27 @@ func (u *userInteractor) RemoveUser(id string) error {
 	user, err := u.repo.FindByID(id)
 	if err != nil {
 		return err
+	} else if user == nil {
+		return domainerr.NotFound("User with id: %s not found", id)
 	}
 	return u.repo.Delete(user)
 }
 
+// RestoreUser brings back a removed user, unless someone registered with the
+// same email meanwhile
+func (u *userInteractor) RestoreUser(id string) error {
+	user, err := u.repo.FindDeletedByID(id)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return domainerr.NotFound("Removed user with id: %s not found", id)
+	}
+	if err := u.service.Duplicated(user.GetEmail()); err != nil {
+		return err
+	}
+	return u.repo.Restore(user)
+}
+
 func (u *userInteractor) FindByID(id string) (*User, error) {
 	user, err := u.repo.FindByID(id)
 	if err != nil {
@@ -84,8 +103,10 @@ func toUser(users []*model.User) []*User {
 	res := make([]*User, len(users))
 	for i, user := range users {
 		res[i] = &User{
-			ID:    user.GetID(),
-			Email: user.GetEmail(),
+			ID:       user.GetID(),
+			Email:    user.GetEmail(),
+			Name:     user.GetName(),
+			LastName: user.GetLastName(),
 		}
 	}
 	return res
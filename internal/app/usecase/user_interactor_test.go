@@ -1,11 +1,13 @@
 package usecase_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ramonmacias/librarium/internal/app/domain/service"
 	"github.com/ramonmacias/librarium/internal/app/interface/persistence/memory"
 	"github.com/ramonmacias/librarium/internal/app/usecase"
+	"github.com/ramonmacias/librarium/internal/domainerr"
 )
 
 var (
@@ -89,3 +91,66 @@ func TestFindUser(t *testing.T) {
 		t.Errorf("No user should return a user an error nil but got user %v err %v", user, err)
 	}
 }
+
+func TestListUsersSortedByLastName(t *testing.T) {
+	userController := memory.NewUserController()
+	interactor := usecase.NewUserInteractor(userController, service.NewUserService(userController))
+	interactor.RegisterUser("grace@test.com", "Grace", "Hopper")
+	interactor.RegisterUser("ada@test.com", "Ada", "Lovelace")
+	interactor.RegisterUser("alan@test.com", "Alan", "Turing")
+	interactor.RegisterUser("edsger@test.com", "Edsger", "Dijkstra")
+
+	users, err := interactor.ListUser()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	for i, lastName := range []string{"Dijkstra", "Hopper", "Lovelace", "Turing"} {
+		if users[i].LastName != lastName {
+			t.Errorf("The user in position %d should be %s but got %s", i, lastName, users[i].LastName)
+		}
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	userController := memory.NewUserController()
+	interactor := usecase.NewUserInteractor(userController, service.NewUserService(userController))
+	interactor.RegisterUser("ada@test.com", "Ada", "Lovelace")
+	users, _ := interactor.ListUser()
+	id := users[0].ID
+
+	if err := interactor.RestoreUser(id); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error for an active user but got %v", err)
+	}
+
+	interactor.RemoveUser(id)
+	interactor.RegisterUser("ada@test.com", "Ada", "King")
+	if err := interactor.RestoreUser(id); !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error when the email is taken but got %v", err)
+	}
+
+	users, _ = interactor.ListUser()
+	interactor.RemoveUser(users[0].ID)
+	if err := interactor.RestoreUser(id); err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if user, _ := interactor.FindByID(id); user == nil || user.LastName != "Lovelace" {
+		t.Errorf("Should find the restored user but got %v", user)
+	}
+}
+
+func TestRegisterUserDuplicatedEmailIgnoresCase(t *testing.T) {
+	userController := memory.NewUserController()
+	interactor := usecase.NewUserInteractor(userController, service.NewUserService(userController))
+	if err := interactor.RegisterUser("ada@test.com", "Ada", "Lovelace"); err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+
+	err := interactor.RegisterUser("Ada@Test.com", "Ada", "King")
+	if !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error but got %v", err)
+	}
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) || domainErr.ResourceID() == "" {
+		t.Errorf("The conflict should point to the existing user but got %v", err)
+	}
+}
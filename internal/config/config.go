@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAddress            = "0.0.0.0:8080"
+	defaultCompressionMinSize = 1024
+	defaultSlowQueryThreshold = 200 * time.Millisecond
+	defaultBreakerThreshold   = 5
+	defaultBreakerTimeout     = 30 * time.Second
+	defaultStatsCacheTTL      = 30 * time.Second
+	defaultDrainPeriod        = 5 * time.Second
+	defaultSRUURL             = "http://lx2.loc.gov:210/LCDB"
+)
+
+// Config holds all the settings needed to run the application
+type Config struct {
+	Address string
+	// CompressionMinSize is the minimum size in bytes a response needs to
+	// have to be compressed
+	CompressionMinSize int
+	// BodyLogSampleRate is the fraction of requests, from 0 to 1, whose
+	// bodies are logged to debug the client integrations
+	BodyLogSampleRate float64
+	// StorageCallThreshold logs a warning for the requests that make more
+	// storage calls, zero disables the warning
+	StorageCallThreshold int
+	// StorageCallHeader sends the number of storage calls of each request on
+	// the X-Storage-Calls header, meant for debugging
+	StorageCallHeader bool
+	// DebugVars serves the expvar metrics on /debug/vars, it should only be
+	// enabled when the server is not reachable from outside
+	DebugVars bool
+	// StatsCacheTTL is how long the stats are cached before counting again
+	StatsCacheTTL time.Duration
+	// SRUURL is the SRU server used to copy the books data from
+	SRUURL string
+	// ExportSigningKey signs the availability exports with HMAC-SHA256, they
+	// are not signed when it's empty
+	ExportSigningKey string
+	// DrainPeriod is how long the server keeps serving after reporting it's
+	// not ready, so the load balancer stops sending traffic before shutting down
+	DrainPeriod time.Duration
+	// RunMigrations migrates the postgres tables before accepting traffic
+	RunMigrations bool
+	// SQLitePath enables the embedded sqlite storage when it's not empty
+	SQLitePath string
+	// Postgres is nil on the installs that only use sqlite
+	Postgres *Postgres
+	Breaker  Breaker
+}
+
+// Breaker holds the settings of the circuit breakers around the databases
+type Breaker struct {
+	// Threshold is the number of consecutive failures that opens the breaker
+	Threshold int
+	// Timeout is how long the breaker stays open before probing again
+	Timeout time.Duration
+}
+
+// Postgres holds the settings needed to connect to the database
+type Postgres struct {
+	Host       string
+	Port       string
+	User       string
+	Database   string
+	Password   string
+	ReplicaDSN string
+	// SlowQueryThreshold is the duration after which a query gets logged,
+	// zero disables the logging
+	SlowQueryThreshold time.Duration
+}
+
+// SecretProvider resolves the secrets from an external store, like Vault or a
+// KMS, it returns an empty value when the store doesn't have the key
+type SecretProvider interface {
+	Secret(key string) (string, error)
+}
+
+// Load reads the configuration from the environment, it returns an error
+// listing all the required values that are missing
+func Load() (*Config, error) {
+	return LoadWithSecrets(nil)
+}
+
+// LoadWithSecrets works like Load but the secrets not found on the
+// environment, directly or through a KEY_FILE path, are asked to the provider
+func LoadWithSecrets(secrets SecretProvider) (*Config, error) {
+	l := &loader{secrets: secrets}
+	sqlitePath := l.optional("SQLITE_PATH", "")
+	cfg := &Config{
+		Address:              l.optional("SERVER_ADDRESS", defaultAddress),
+		CompressionMinSize:   l.optionalInt("COMPRESSION_MIN_SIZE", defaultCompressionMinSize, 0),
+		BodyLogSampleRate:    l.optionalRate("LOG_BODY_SAMPLE_RATE", 0),
+		StorageCallThreshold: l.optionalInt("STORAGE_CALL_THRESHOLD", 0, 0),
+		StorageCallHeader:    l.optionalBool("STORAGE_CALL_HEADER", false),
+		DebugVars:            l.optionalBool("DEBUG_VARS", false),
+		StatsCacheTTL:        l.optionalDuration("STATS_CACHE_TTL", defaultStatsCacheTTL),
+		SRUURL:               l.optional("SRU_URL", defaultSRUURL),
+		ExportSigningKey:     l.optionalSecret("EXPORT_SIGNING_KEY"),
+		DrainPeriod:          l.optionalDuration("SHUTDOWN_DRAIN_PERIOD", defaultDrainPeriod),
+		RunMigrations:        l.optionalBool("RUN_MIGRATIONS", false),
+		SQLitePath:           sqlitePath,
+		Postgres:             l.postgres(sqlitePath != ""),
+		Breaker: Breaker{
+			Threshold: l.optionalInt("BREAKER_THRESHOLD", defaultBreakerThreshold, 1),
+			Timeout:   l.optionalDuration("BREAKER_TIMEOUT", defaultBreakerTimeout),
+		},
+	}
+	if len(l.missing) > 0 {
+		return nil, fmt.Errorf("Missing required configuration values: %s", strings.Join(l.missing, ", "))
+	}
+	if len(l.invalid) > 0 {
+		return nil, fmt.Errorf("Invalid configuration values: %s", strings.Join(l.invalid, ", "))
+	}
+	return cfg, nil
+}
+
+type loader struct {
+	secrets SecretProvider
+	missing []string
+	invalid []string
+}
+
+// postgres reads the postgres settings, they are only required when there
+// isn't another database, so a sqlite install can leave POSTGRES_HOST empty
+func (l *loader) postgres(hasSQLite bool) *Postgres {
+	if hasSQLite && os.Getenv("POSTGRES_HOST") == "" {
+		return nil
+	}
+	return &Postgres{
+		Host:               l.required("POSTGRES_HOST"),
+		Port:               l.required("POSTGRES_PORT"),
+		User:               l.required("POSTGRES_USER"),
+		Database:           l.required("POSTGRES_DATABASE"),
+		Password:           l.requiredSecret("POSTGRES_PASSWORD"),
+		ReplicaDSN:         l.optionalSecret("POSTGRES_REPLICA_DSN"),
+		SlowQueryThreshold: l.optionalDuration("POSTGRES_SLOW_QUERY_THRESHOLD", defaultSlowQueryThreshold),
+	}
+}
+
+func (l *loader) requiredSecret(key string) string {
+	value, failed := l.secret(key)
+	if value == "" && !failed {
+		l.missing = append(l.missing, key)
+	}
+	return value
+}
+
+func (l *loader) optionalSecret(key string) string {
+	value, _ := l.secret(key)
+	return value
+}
+
+// secret looks for the value on the environment, then on the file pointed by
+// KEY_FILE, like the Docker secrets, and finally on the provider. An empty
+// file counts as a missing value, failed tells the error is already reported
+func (l *loader) secret(key string) (value string, failed bool) {
+	if value := os.Getenv(key); value != "" {
+		return value, false
+	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			l.invalid = append(l.invalid, key+"_FILE")
+			return "", true
+		}
+		if value := strings.TrimRight(string(content), "\r\n"); value != "" {
+			return value, false
+		}
+	}
+	if l.secrets != nil {
+		value, err := l.secrets.Secret(key)
+		if err != nil {
+			l.invalid = append(l.invalid, key)
+			return "", true
+		}
+		return value, false
+	}
+	return "", false
+}
+
+func (l *loader) required(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		l.missing = append(l.missing, key)
+	}
+	return value
+}
+
+func (l *loader) optional(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// optionalInt reads a number that can't be lower than min
+func (l *loader) optionalInt(key string, defaultValue, min int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < min {
+		l.invalid = append(l.invalid, key)
+		return defaultValue
+	}
+	return i
+}
+
+// optionalRate reads a fraction between 0 and 1
+func (l *loader) optionalRate(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 1 {
+		l.invalid = append(l.invalid, key)
+		return defaultValue
+	}
+	return f
+}
+
+func (l *loader) optionalDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		l.invalid = append(l.invalid, key)
+		return defaultValue
+	}
+	return d
+}
+
+func (l *loader) optionalBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid = append(l.invalid, key)
+		return defaultValue
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramonmacias/librarium/internal/config"
+)
+
+func setRequired(t *testing.T) {
+	t.Setenv("POSTGRES_HOST", "localhost")
+	t.Setenv("POSTGRES_PORT", "5432")
+	t.Setenv("POSTGRES_USER", "user")
+	t.Setenv("POSTGRES_DATABASE", "database")
+	t.Setenv("POSTGRES_PASSWORD", "password")
+}
+
+func TestLoadConfig(t *testing.T) {
+	setRequired(t)
+	t.Setenv("SERVER_ADDRESS", "")
+	t.Setenv("COMPRESSION_MIN_SIZE", "")
+	t.Setenv("SHUTDOWN_DRAIN_PERIOD", "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Errorf("Shouldn't be an error but got %v", err)
+	}
+	if cfg.Address != "0.0.0.0:8080" {
+		t.Errorf("Should use the default address but got %s", cfg.Address)
+	}
+	if cfg.CompressionMinSize != 1024 {
+		t.Errorf("Should use the default compression min size but got %d", cfg.CompressionMinSize)
+	}
+	if cfg.DrainPeriod != 5*time.Second {
+		t.Errorf("Should use the default drain period but got %v", cfg.DrainPeriod)
+	}
+	if cfg.Postgres.Host != "localhost" {
+		t.Errorf("The host should be localhost but got %s", cfg.Postgres.Host)
+	}
+}
+
+func TestLoadConfigMissingValues(t *testing.T) {
+	setRequired(t)
+	t.Setenv("POSTGRES_HOST", "")
+	t.Setenv("POSTGRES_PASSWORD", "")
+
+	cfg, err := config.Load()
+	if err == nil {
+		t.Fatalf("Should be an error but got config %v", cfg)
+	}
+	if !strings.Contains(err.Error(), "POSTGRES_HOST") || !strings.Contains(err.Error(), "POSTGRES_PASSWORD") {
+		t.Errorf("The error should report all the missing values but got %v", err)
+	}
+}
+
+func TestLoadConfigInvalidValues(t *testing.T) {
+	setRequired(t)
+	t.Setenv("COMPRESSION_MIN_SIZE", "big")
+	t.Setenv("BREAKER_THRESHOLD", "0")
+
+	cfg, err := config.Load()
+	if err == nil {
+		t.Fatalf("Should be an error but got config %v", cfg)
+	}
+	if !strings.Contains(err.Error(), "COMPRESSION_MIN_SIZE") || !strings.Contains(err.Error(), "BREAKER_THRESHOLD") {
+		t.Errorf("The error should report the invalid values but got %v", err)
+	}
+}
+
+func TestLoadConfigSQLiteOnly(t *testing.T) {
+	setRequired(t)
+	t.Setenv("POSTGRES_HOST", "")
+	t.Setenv("POSTGRES_PASSWORD", "")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "librarium.db"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if cfg.Postgres != nil {
+		t.Errorf("Postgres shouldn't be configured but got %v", cfg.Postgres)
+	}
+
+	t.Setenv("POSTGRES_HOST", "localhost")
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "POSTGRES_PASSWORD") {
+		t.Errorf("Postgres should be complete once the host is set but got %v", err)
+	}
+}
+
+type fakeSecretProvider map[string]string
+
+func (f fakeSecretProvider) Secret(key string) (string, error) {
+	return f[key], nil
+}
+
+func TestLoadConfigSecretFromFile(t *testing.T) {
+	setRequired(t)
+	path := filepath.Join(t.TempDir(), "postgres_password")
+	os.WriteFile(path, []byte("filePassword\n"), 0600)
+	t.Setenv("POSTGRES_PASSWORD", "")
+	t.Setenv("POSTGRES_PASSWORD_FILE", path)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if cfg.Postgres.Password != "filePassword" {
+		t.Errorf("Should read the password from the file but got %q", cfg.Postgres.Password)
+	}
+
+	t.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "POSTGRES_PASSWORD_FILE") {
+		t.Errorf("The error should report the unreadable file but got %v", err)
+	}
+
+	os.WriteFile(path, []byte("\n"), 0600)
+	t.Setenv("POSTGRES_PASSWORD_FILE", path)
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "Missing required configuration values: POSTGRES_PASSWORD") {
+		t.Errorf("An empty file should be a missing password but got %v", err)
+	}
+}
+
+func TestLoadConfigReplicaFromFile(t *testing.T) {
+	setRequired(t)
+	path := filepath.Join(t.TempDir(), "postgres_replica_dsn")
+	os.WriteFile(path, []byte("host=replica port=5433\n"), 0600)
+	t.Setenv("POSTGRES_REPLICA_DSN", "")
+	t.Setenv("POSTGRES_REPLICA_DSN_FILE", path)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if cfg.Postgres.ReplicaDSN != "host=replica port=5433" {
+		t.Errorf("Should read the replica DSN from the file but got %q", cfg.Postgres.ReplicaDSN)
+	}
+}
+
+func TestLoadConfigSecretFromProvider(t *testing.T) {
+	setRequired(t)
+	t.Setenv("POSTGRES_PASSWORD", "")
+
+	cfg, err := config.LoadWithSecrets(fakeSecretProvider{"POSTGRES_PASSWORD": "vaultPassword"})
+	if err != nil {
+		t.Fatalf("Shouldn't be an error but got %v", err)
+	}
+	if cfg.Postgres.Password != "vaultPassword" {
+		t.Errorf("Should get the password from the provider but got %q", cfg.Postgres.Password)
+	}
+
+	if _, err := config.LoadWithSecrets(fakeSecretProvider{}); err == nil || !strings.Contains(err.Error(), "POSTGRES_PASSWORD") {
+		t.Errorf("The error should report the missing secret but got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package domainerr
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Sentinel errors used to classify the errors returned by the domain, use
+// errors.Is to check which kind of error we got
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation")
+	ErrForbidden   = errors.New("forbidden")
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error is a domain error with a human readable message and the kind of
+// error it belongs to
+type Error struct {
+	kind       error
+	msg        string
+	resourceID string
+	retryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// ResourceID returns the id of the resource related with the error, for
+// example the one already stored on a conflict
+func (e *Error) ResourceID() string {
+	return e.resourceID
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// RetryAfter returns how long the client should wait before trying again,
+// it's only set on unavailable errors
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func NotFound(format string, a ...interface{}) error {
+	return newError(ErrNotFound, format, a...)
+}
+
+func Conflict(format string, a ...interface{}) error {
+	return newError(ErrConflict, format, a...)
+}
+
+// ConflictWith returns a conflict error that keeps the id of the resource
+// already stored
+func ConflictWith(id string, format string, a ...interface{}) error {
+	return &Error{
+		kind:       ErrConflict,
+		msg:        fmt.Sprintf(format, a...),
+		resourceID: id,
+	}
+}
+
+func Validation(format string, a ...interface{}) error {
+	return newError(ErrValidation, format, a...)
+}
+
+func Forbidden(format string, a ...interface{}) error {
+	return newError(ErrForbidden, format, a...)
+}
+
+// Unavailable returns an error for a dependency that is temporarily down,
+// retryAfter tells when it's worth trying again
+func Unavailable(retryAfter time.Duration, format string, a ...interface{}) error {
+	return &Error{
+		kind:       ErrUnavailable,
+		msg:        fmt.Sprintf(format, a...),
+		retryAfter: retryAfter,
+	}
+}
+
+func newError(kind error, format string, a ...interface{}) error {
+	return &Error{
+		kind: kind,
+		msg:  fmt.Sprintf(format, a...),
+	}
+}
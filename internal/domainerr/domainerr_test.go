@@ -0,0 +1,35 @@
+package domainerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ramonmacias/librarium/internal/domainerr"
+)
+
+func TestErrorKinds(t *testing.T) {
+	err := domainerr.NotFound("User with id: %s not found", "id")
+	if !errors.Is(err, domainerr.ErrNotFound) {
+		t.Errorf("Should be a not found error but got %v", err)
+	}
+	if errors.Is(err, domainerr.ErrConflict) {
+		t.Error("A not found error shouldn't be a conflict error")
+	}
+	if err.Error() != "User with id: id not found" {
+		t.Errorf("Should keep the message but got %s", err.Error())
+	}
+}
+
+func TestConflictWithResource(t *testing.T) {
+	err := domainerr.ConflictWith("bookID", "%s already exists", "isbn")
+	if !errors.Is(err, domainerr.ErrConflict) {
+		t.Errorf("Should be a conflict error but got %v", err)
+	}
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) {
+		t.Fatalf("Should be a domain error but got %v", err)
+	}
+	if domainErr.ResourceID() != "bookID" {
+		t.Errorf("Should keep the resource id but got %s", domainErr.ResourceID())
+	}
+}
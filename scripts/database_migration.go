@@ -1,11 +1,22 @@
 package main
 
 import (
+	"log"
+
 	"github.com/ramonmacias/librarium/internal/app/interface/persistence/postgres"
+	"github.com/ramonmacias/librarium/internal/config"
 )
 
 func main() {
-	db := postgres.NewClient("localhost", "5432", "ramon", "librarium_database", "ramon_postgres_pass").Connect().DB()
-	db.AutoMigrate(&postgres.User{})
-	db.AutoMigrate(&postgres.Book{})
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Error loading the configuration: %v", err)
+	}
+	if cfg.Postgres == nil {
+		log.Fatal("Postgres is not configured, set the POSTGRES_* values")
+	}
+	conn := postgres.NewClient(cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Database, cfg.Postgres.Password).Connect()
+	if err := conn.Migrate(); err != nil {
+		log.Fatalf("Error running the migrations: %v", err)
+	}
 }